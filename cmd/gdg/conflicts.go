@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/conflicts"
+	"strings"
+)
+
+func runConflicts(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("conflicts", flag.ExitOnError)
+	rule := flags.String("rule", "", "conflict rule id, e.g. slf4j-bindings")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 && flags.NArg() != 2 {
+		return fmt.Errorf("expected [<org/repo> <ref>]")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	result, err := c.Conflicts(flags.Arg(0), flags.Arg(1), *rule)
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"repo", "ref", "rule", "variants", "artifacts"}}
+	for _, conflict := range result {
+		var families []string
+		for _, family := range conflict.Families {
+			families = append(families, family.Family+": "+strings.Join(family.Artifacts, " "))
+		}
+		t.add(conflict.Repo, conflict.Ref, conflict.Rule, strings.Join(conflict.Variants, " "), strings.Join(families, "; "))
+	}
+	if result == nil {
+		result = []conflicts.Conflict{}
+	}
+	return cfg.print(result, t)
+}
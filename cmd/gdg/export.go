@@ -0,0 +1,304 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gradle-serverless-dependencies-graph/lib/archive"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+)
+
+func runExport(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	path := flags.String("file", "-", "archive file, .gz suffix enables compression")
+	flags.Parse(args)
+
+	storageSvc, err := cfg.storage()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *path != "-" {
+		file, err := os.Create(*path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	w, err := archive.NewWriter(out, strings.HasSuffix(*path, ".gz"))
+	if err != nil {
+		return err
+	}
+
+	if err := storageSvc.ScanStorage("export", func(item storage.StorageDto) error {
+		return w.Write(archive.TableStorage, item)
+	}); err != nil {
+		return err.Err
+	}
+	if err := storageSvc.ScanDependencies("export", func(item storage.DependencyDto) error {
+		return w.Write(archive.TableDependencies, item)
+	}); err != nil {
+		return err.Err
+	}
+	if err := storageSvc.ScanRepositories("export", func(item storage.RepositoryDto) error {
+		return w.Write(archive.TableRepositories, item)
+	}); err != nil {
+		return err.Err
+	}
+
+	tables := rawTables(storageSvc)
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := storageSvc.ScanItems("export", tables[name], func(attrs map[string]types.AttributeValue) error {
+			item, err := archive.EncodeItem(attrs)
+			if err != nil {
+				return err
+			}
+			return w.Write(name, item)
+		}); err != nil {
+			return err.Err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d storage, %d dependencies, %d repositories items\n",
+		w.Count[archive.TableStorage], w.Count[archive.TableDependencies], w.Count[archive.TableRepositories])
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "exported %d %s items\n", w.Count[name], name)
+	}
+	return nil
+}
+
+func (cfg *config) storage() (*storage.Storage, error) {
+	table := func(name string) *string {
+		value := envOrDefault("DYNAMODB_TABLE_"+strings.ToUpper(name), cfg.tablePrefix+"-"+name)
+		return &value
+	}
+	storageCfg := storage.StorageConfig{
+		StorageTableName:      table("storage"),
+		DependenciesTableName: table("dependencies"),
+		RepositoriesTableName: table("repositories"),
+		TokensTableName:       table("tokens"),
+		AuditTableName:        table("audit"),
+		AdvisoriesTableName:   table("advisories"),
+		PoliciesTableName:     table("policies"),
+		ViolationsTableName:   table("violations"),
+		LicensesTableName:     table("licenses"),
+		MetadataTableName:     table("metadata"),
+		FreshnessTableName:    table("freshness"),
+		ProducersTableName:    table("producers"),
+		WebhooksTableName:     table("webhooks"),
+		DeliveriesTableName:   table("deliveries"),
+	}
+
+	logger, err := helpers.InitLogger(envOrDefault("GDG_LOG_LEVEL", "ERROR"), false)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewStorage(storageCfg, logger)
+}
+
+// audit stores entry of CLI import, principal is local user as CLI bypasses authorizer
+func (cfg *config) audit(storageSvc *storage.Storage, action string, target string, count int) error {
+	name := "unknown"
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+	hostname, _ := os.Hostname()
+	if err := storageSvc.PutAudit(action, storage.AuditDto{
+		Principal: fmt.Sprintf("cli:%s", name),
+		Action:    action,
+		Target:    target,
+		Count:     count,
+		RequestId: fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+	}); err != nil {
+		return err.Err
+	}
+	return nil
+}
+
+// rawTables maps archive tables stored as raw items to configured DynamoDB tables
+func rawTables(storageSvc *storage.Storage) map[string]string {
+	c := storageSvc.Config
+	return map[string]string{
+		archive.TableTokens:     *c.TokensTableName,
+		archive.TableAudit:      *c.AuditTableName,
+		archive.TableAdvisories: *c.AdvisoriesTableName,
+		archive.TablePolicies:   *c.PoliciesTableName,
+		archive.TableViolations: *c.ViolationsTableName,
+		archive.TableLicenses:   *c.LicensesTableName,
+		archive.TableMetadata:   *c.MetadataTableName,
+		archive.TableFreshness:  *c.FreshnessTableName,
+		archive.TableProducers:  *c.ProducersTableName,
+		archive.TableWebhooks:   *c.WebhooksTableName,
+	}
+}
+
+func (cfg *config) graph() (*graph.Graph, error) {
+	file, err := os.Open(cfg.offline)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r, err := archive.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	return graph.Load(r)
+}
+
+func runImport(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	path := flags.String("file", "-", "archive file produced by export")
+	batchSize := flags.Int("batch", 500, "items written per storage call")
+	flags.Parse(args)
+
+	storageSvc, err := cfg.storage()
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if *path != "-" {
+		file, err := os.Open(*path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	r, err := archive.NewReader(in)
+	if err != nil {
+		return err
+	}
+
+	var storageItems []storage.StorageDto
+	var dependencyItems []storage.DependencyDto
+	var repositoryItems []storage.RepositoryDto
+	rawItems := make(map[string][]map[string]types.AttributeValue)
+	tables := rawTables(storageSvc)
+	count := make(map[string]int)
+
+	flush := func(force bool) error {
+		if len(storageItems) > 0 && (force || len(storageItems) >= *batchSize) {
+			if _, err := storageSvc.ImportStorage("import", storageItems); err != nil {
+				return err.Err
+			}
+			count[archive.TableStorage] += len(storageItems)
+			storageItems = storageItems[:0]
+		}
+		if len(dependencyItems) > 0 && (force || len(dependencyItems) >= *batchSize) {
+			if _, err := storageSvc.ImportDependencies("import", dependencyItems); err != nil {
+				return err.Err
+			}
+			count[archive.TableDependencies] += len(dependencyItems)
+			dependencyItems = dependencyItems[:0]
+		}
+		if len(repositoryItems) > 0 && (force || len(repositoryItems) >= *batchSize) {
+			if _, err := storageSvc.ImportRepositories("import", repositoryItems); err != nil {
+				return err.Err
+			}
+			count[archive.TableRepositories] += len(repositoryItems)
+			repositoryItems = repositoryItems[:0]
+		}
+		for name, items := range rawItems {
+			if len(items) > 0 && (force || len(items) >= *batchSize) {
+				if _, err := storageSvc.ImportItems("import", tables[name], items); err != nil {
+					return err.Err
+				}
+				count[name] += len(items)
+				rawItems[name] = items[:0]
+			}
+		}
+		return nil
+	}
+
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch record.Table {
+		case archive.TableStorage:
+			var item storage.StorageDto
+			if err := record.Decode(&item); err != nil {
+				return err
+			}
+			storageItems = append(storageItems, item)
+		case archive.TableDependencies:
+			var item storage.DependencyDto
+			if err := record.Decode(&item); err != nil {
+				return err
+			}
+			dependencyItems = append(dependencyItems, item)
+		case archive.TableRepositories:
+			var item storage.RepositoryDto
+			if err := record.Decode(&item); err != nil {
+				return err
+			}
+			repositoryItems = append(repositoryItems, item)
+		default:
+			if _, ok := tables[record.Table]; !ok {
+				return fmt.Errorf("unknown table %q in archive", record.Table)
+			}
+			var item archive.Item
+			if err := record.Decode(&item); err != nil {
+				return err
+			}
+			attrs, err := archive.DecodeItem(item)
+			if err != nil {
+				return err
+			}
+			rawItems[record.Table] = append(rawItems[record.Table], attrs)
+		}
+
+		if err := flush(false); err != nil {
+			return err
+		}
+	}
+	if err := flush(true); err != nil {
+		return err
+	}
+
+	total := 0
+	for _, n := range count {
+		total += n
+	}
+	if err := cfg.audit(storageSvc, storage.AuditActionImport, *path, total); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d storage, %d dependencies, %d repositories items from archive v%d\n",
+		count[archive.TableStorage], count[archive.TableDependencies], count[archive.TableRepositories], r.Header.Version)
+	names := make([]string, 0, len(rawItems))
+	for name := range rawItems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "imported %d %s items\n", count[name], name)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// runFreshness prints libyears and share of current dependencies, per org and repo/ref or history of single repo/ref
+func runFreshness(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("freshness", flag.ExitOnError)
+	org := flags.String("org", "", "limit repo/refs to org")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 && flags.NArg() != 2 {
+		return fmt.Errorf("expected [<org/repo> <ref>]")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"id", "date", "libyears", "current", "resolved", "dependencies"}}
+	if flags.NArg() == 2 {
+		history, err := c.FreshnessHistory(flags.Arg(0), flags.Arg(1))
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range history {
+			t.add(snapshot.Id, snapshot.Date, formatLibyears(snapshot.Libyears), formatShare(snapshot.CurrentShare),
+				strconv.Itoa(snapshot.Resolved), strconv.Itoa(snapshot.Dependencies))
+		}
+		return cfg.print(history, t)
+	}
+
+	dashboard, err := c.Freshness(*org)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range append(dashboard.Orgs, dashboard.Refs...) {
+		t.add(snapshot.Id, snapshot.Date, formatLibyears(snapshot.Libyears), formatShare(snapshot.CurrentShare),
+			strconv.Itoa(snapshot.Resolved), strconv.Itoa(snapshot.Dependencies))
+	}
+	return cfg.print(dashboard, t)
+}
+
+func formatLibyears(libyears float64) string {
+	return strconv.FormatFloat(libyears, 'f', 1, 64)
+}
+
+func formatShare(share float64) string {
+	return fmt.Sprintf("%.0f%%", share*100)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+	"strings"
+)
+
+// runLicenseImport stores mapping used for dependencies uploaded without licenses
+func runLicenseImport(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("license-import", flag.ExitOnError)
+	batchSize := flags.Int("batch", 500, "items written per storage call")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected <mapping-file>")
+	}
+	items, err := licenses.LoadMapping(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	storageSvc, err := cfg.storage()
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(items); start += *batchSize {
+		end := start + *batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		if _, err := storageSvc.ImportLicenses("license-import", items[start:end]); err != nil {
+			return err.Err
+		}
+	}
+	if err := cfg.audit(storageSvc, storage.AuditActionLicenseImport, flags.Arg(0), len(items)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d license mappings, they apply to next uploads\n", len(items))
+	return nil
+}
+
+func runLicenses(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("licenses", flag.ExitOnError)
+	license := flags.String("license", "", "SPDX id, family (GPL, AGPL) or UNKNOWN")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 && flags.NArg() != 2 {
+		return fmt.Errorf("expected [<org/repo> <ref>]")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	usages, err := c.Licenses(flags.Arg(0), flags.Arg(1), *license)
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"license", "category", "refs", "dependencies"}}
+	for _, usage := range usages {
+		var deps []string
+		for _, dep := range usage.Dependencies {
+			deps = append(deps, dep.Dependency+":"+dep.Version)
+		}
+		t.add(usage.License, usage.Category, strings.Join(usage.Repos, " "), strings.Join(deps, " "))
+	}
+	return cfg.print(usages, t)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/client"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+var version = "dev"
+
+type backend interface {
+	Dependencies(repo string, ref string, filter *storage.StorageFilter) ([]storage.StorageDto, error)
+	WhoUses(dependency string, version *string, filter *storage.StorageFilter) ([]storage.StorageDto, error)
+	DependencyNames(group string) ([]string, error)
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(cfg *config, args []string) error
+}
+
+type config struct {
+	url         string
+	output      string
+	offline     string
+	tablePrefix string
+}
+
+var commands = []command{
+	{"upload", "upload [-format auto|json|cyclonedx|lockfile|gradle] [-produces group:name,...] <org/repo> <ref> <file>...", runUpload},
+	{"deps", "deps [-variant name] [-build-type type] [-flavor name] <org/repo> <ref>", runDeps},
+	{"who-uses", "who-uses [-variant name] <group:name[:version]>", runWhoUses},
+	{"diff", "diff [-other-repo org/repo] <org/repo> <ref> <other-ref>", runDiff},
+	{"drift", "drift <group[:name]>", runDrift},
+	{"path", "path <org/repo> <ref> <group:name>", runPath},
+	{"repo-graph", "repo-graph [<org/repo>]", runRepoGraph},
+	{"impact", "impact <group:name> <version>", runImpact},
+	{"export", "export [-file graph.jsonl.gz]", runExport},
+	{"import", "import [-file graph.jsonl.gz] [-batch 500]", runImport},
+	{"osv-import", "osv-import [-batch 500] <maven-osv.zip|dir>", runOsvImport},
+	{"license-import", "license-import [-batch 500] <mapping-file>", runLicenseImport},
+	{"licenses", "licenses [-license id] [<org/repo> <ref>]", runLicenses},
+	{"conflicts", "conflicts [-rule id] [<org/repo> <ref>]", runConflicts},
+	{"freshness", "freshness [-org name] [<org/repo> <ref>]", runFreshness},
+	{"sarif", "sarif [-file build.gradle] [-out results.sarif] <org/repo> <ref>", runSarif},
+	{"policy-check", "policy-check <policy-file> <org/repo> <ref> <file>...", runPolicyCheck},
+	{"policy-set", "policy-set <policy-file>", runPolicySet},
+	{"violations", "violations [<org/repo> <ref>]", runViolations},
+	{"webhooks", "webhooks", runWebhooks},
+	{"webhook-add", "webhook-add [-repos org/*,...] [-refs main,...] [-artifacts group:*,...] [-description text] <url>", runWebhookAdd},
+	{"webhook-delete", "webhook-delete <id>", runWebhookDelete},
+	{"hash-password", "hash-password < password", runHashPassword},
+	{"version", "version", runVersion},
+}
+
+func main() {
+	cfg := &config{}
+	flags := flag.NewFlagSet("gdg", flag.ExitOnError)
+	flags.StringVar(&cfg.url, "url", os.Getenv("GDG_URL"), "API base url, GDG_URL")
+	flags.StringVar(&cfg.output, "o", "table", "output format: table, json or csv")
+	flags.StringVar(&cfg.offline, "offline", os.Getenv("GDG_OFFLINE"), "answer queries from exported file instead of API, GDG_OFFLINE")
+	flags.StringVar(&cfg.tablePrefix, "table-prefix", envOrDefault("GDG_TABLE_PREFIX", "gradle-dependencies"), "DynamoDB tables prefix for export, GDG_TABLE_PREFIX")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: gdg [flags] <command> [args]\n\nFlags:\n")
+		flags.PrintDefaults()
+		fmt.Fprintf(flags.Output(), "\nCommands:\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(flags.Output(), "  %s\n", cmd.usage)
+		}
+		fmt.Fprintf(flags.Output(), "\nCredentials are read from GDG_TOKEN, GDG_USER/GDG_PASSWORD or netrc file (GDG_NETRC, ~/.netrc)\n")
+		fmt.Fprintf(flags.Output(), "export/import work with DynamoDB directly using AWS credentials, -table-prefix selects deployment\n")
+		fmt.Fprintf(flags.Output(), "export covers all tables except transient webhook deliveries, archive holds token hashes and webhook secrets\n")
+	}
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if cfg.output != "table" && cfg.output != "json" && cfg.output != "csv" {
+		fmt.Fprintf(os.Stderr, "gdg: unknown output format %q\n", cfg.output)
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == flags.Arg(0) {
+			if err := cmd.run(cfg, flags.Args()[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "gdg %s: %s\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "gdg: unknown command %q\n", flags.Arg(0))
+	flags.Usage()
+	os.Exit(2)
+}
+
+func (cfg *config) client() (*client.Client, error) {
+	user, password := os.Getenv("GDG_USER"), os.Getenv("GDG_PASSWORD")
+	if user == "" {
+		user, password = netrcCredentials(cfg.url)
+	}
+	c, err := client.NewClient(cfg.url, user, password)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = os.Getenv("GDG_TOKEN")
+	return c, nil
+}
+
+func (cfg *config) backend() (backend, error) {
+	if cfg.offline != "" {
+		return cfg.graph()
+	}
+	return cfg.client()
+}
+
+func envOrDefault(name string, value string) string {
+	if val, ok := os.LookupEnv(name); ok {
+		return val
+	}
+	return value
+}
+
+func netrcCredentials(apiUrl string) (string, string) {
+	parsed, err := url.Parse(apiUrl)
+	if err != nil {
+		return "", ""
+	}
+
+	path := os.Getenv("GDG_NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", ""
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", ""
+	}
+	defer file.Close()
+
+	user, password, _ := client.NetrcCredentials(file, parsed.Hostname())
+	return user, password
+}
+
+func runVersion(cfg *config, args []string) error {
+	fmt.Println(version)
+	return nil
+}
+
+func filterFlags(flags *flag.FlagSet) *storage.StorageFilter {
+	filter := &storage.StorageFilter{}
+	flags.StringVar(&filter.Variant, "variant", "", "android variant, e.g. freeRelease")
+	flags.StringVar(&filter.BuildType, "build-type", "", "android build type")
+	flags.StringVar(&filter.Flavor, "flavor", "", "android product flavor")
+	return filter
+}
+
+func nonEmpty(filter *storage.StorageFilter) *storage.StorageFilter {
+	if *filter == (storage.StorageFilter{}) {
+		return nil
+	}
+	return filter
+}
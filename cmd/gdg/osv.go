@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/osv"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+)
+
+// runOsvImport loads Maven advisories, e.g. https://osv-vulnerabilities.storage.googleapis.com/Maven/all.zip
+func runOsvImport(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("osv-import", flag.ExitOnError)
+	batchSize := flags.Int("batch", 500, "items written per storage call")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected <maven-osv.zip|dir>")
+	}
+
+	advisories, err := osv.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	storageSvc, err := cfg.storage()
+	if err != nil {
+		return err
+	}
+
+	var items []storage.AdvisoryDto
+	count := 0
+	for i, advisory := range advisories {
+		items = append(items, advisory.Items()...)
+		if len(items) >= *batchSize || i == len(advisories)-1 {
+			if _, err := storageSvc.ImportAdvisories("osv-import", items); err != nil {
+				return err.Err
+			}
+			count += len(items)
+			items = items[:0]
+		}
+	}
+	if err := cfg.audit(storageSvc, storage.AuditActionOsvImport, flags.Arg(0), count); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d advisories as %d items\n", len(advisories), count)
+	return nil
+}
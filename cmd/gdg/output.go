@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *table) add(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+// print writes value as JSON or table rows as aligned table / CSV depending on -o flag
+func (cfg *config) print(value interface{}, t *table) error {
+	switch cfg.output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		writer.Write(t.headers)
+		writer.WriteAll(t.rows)
+		return writer.Error()
+	default:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.ToUpper(strings.Join(t.headers, "\t")))
+		for _, row := range t.rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	}
+}
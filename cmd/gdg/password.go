@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/authorization"
+	"os"
+	"strings"
+)
+
+// runHashPassword prints bcrypt hash for terraform `hashed_users` variable, password is read from stdin to keep it out of shell history
+func runHashPassword(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("hash-password", flag.ExitOnError)
+	flags.Parse(args)
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		return fmt.Errorf("password is not provided")
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return fmt.Errorf("password is empty")
+	}
+
+	hash, err := authorization.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hash)
+	return nil
+}
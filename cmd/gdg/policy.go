@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/policy"
+	"gradle-serverless-dependencies-graph/lib/storage"
+)
+
+// runPolicyCheck evaluates policy file locally, e.g. in CI before upload
+func runPolicyCheck(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("policy-check", flag.ExitOnError)
+	format := flags.String("format", "auto", "input format: auto, json, cyclonedx, lockfile or gradle")
+	flags.Parse(args)
+
+	if flags.NArg() < 4 {
+		return fmt.Errorf("expected <policy-file> <org/repo> <ref> <file>...")
+	}
+	rules, err := policy.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	deps, err := readUploads(flags.Args()[3:], *format)
+	if err != nil {
+		return err
+	}
+
+	licenses.NormalizeDependencies(deps)
+	violations := rules.Evaluate(flags.Arg(1), flags.Arg(2), *deps)
+	if err := printViolations(cfg, violations); err != nil {
+		return err
+	}
+	if len(violations) > 0 && rules.Enforce {
+		return fmt.Errorf("%d policy violations, upload would be rejected", len(violations))
+	}
+	return nil
+}
+
+func runPolicySet(cfg *config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected <policy-file>")
+	}
+	rules, err := policy.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	result, err := c.SetPolicy(rules)
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"id", "rules", "enforce", "updated", "updated-by"}}
+	t.add(result.Id, fmt.Sprint(len(rules.Rules)), fmt.Sprint(rules.Enforce), result.Updated, result.UpdatedBy)
+	return cfg.print(result, t)
+}
+
+func runViolations(cfg *config, args []string) error {
+	if len(args) != 0 && len(args) != 2 {
+		return fmt.Errorf("expected [<org/repo> <ref>]")
+	}
+	var repo, ref string
+	if len(args) == 2 {
+		repo, ref = args[0], args[1]
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	violations, err := c.Violations(repo, ref)
+	if err != nil {
+		return err
+	}
+	return printViolations(cfg, violations)
+}
+
+func printViolations(cfg *config, violations []storage.ViolationDto) error {
+	t := &table{headers: []string{"repo", "ref", "rule", "dependency", "version"}}
+	for _, v := range violations {
+		t.add(v.Repo, v.Ref, v.Rule, v.Dependency, v.Version)
+	}
+	if violations == nil {
+		violations = []storage.ViolationDto{}
+	}
+	return cfg.print(violations, t)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"strings"
+)
+
+func runDeps(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("deps", flag.ExitOnError)
+	filter := filterFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected <org/repo> <ref>")
+	}
+
+	b, err := cfg.backend()
+	if err != nil {
+		return err
+	}
+	items, err := b.Dependencies(flags.Arg(0), flags.Arg(1), nonEmpty(filter))
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"kind", "dependency", "version", "variant", "configurations"}}
+	for _, item := range items {
+		t.add(item.Kind, item.Dependency, item.Version, item.Variant, strings.Join(item.Configurations, ","))
+	}
+	return cfg.print(items, t)
+}
+
+func runWhoUses(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("who-uses", flag.ExitOnError)
+	filter := filterFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected <group:name[:version]>")
+	}
+	parts := strings.Split(flags.Arg(0), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("expected <group:name[:version]>, got %q", flags.Arg(0))
+	}
+	var version *string
+	if len(parts) == 3 {
+		version = &parts[2]
+	}
+
+	b, err := cfg.backend()
+	if err != nil {
+		return err
+	}
+	items, err := b.WhoUses(parts[0]+":"+parts[1], version, nonEmpty(filter))
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"repo", "ref", "version", "variant"}}
+	for _, item := range items {
+		t.add(item.Repo, item.Ref, item.Version, item.Variant)
+	}
+	return cfg.print(items, t)
+}
+
+func runDiff(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	otherRepo := flags.String("other-repo", "", "compare with ref of another repository")
+	filter := filterFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 3 {
+		return fmt.Errorf("expected <org/repo> <ref> <other-ref>")
+	}
+	repo := flags.Arg(0)
+	if *otherRepo == "" {
+		*otherRepo = repo
+	}
+
+	b, err := cfg.backend()
+	if err != nil {
+		return err
+	}
+	before, err := b.Dependencies(repo, flags.Arg(1), nonEmpty(filter))
+	if err != nil {
+		return err
+	}
+	after, err := b.Dependencies(*otherRepo, flags.Arg(2), nonEmpty(filter))
+	if err != nil {
+		return err
+	}
+
+	changes := graph.Diff(before, after)
+	t := &table{headers: []string{"change", "kind", "dependency", "variant", "before", "after"}}
+	for _, change := range changes {
+		t.add(change.Change, change.Kind, change.Dependency, change.Variant, change.Before, change.After)
+	}
+	return cfg.print(changes, t)
+}
+
+func runDrift(cfg *config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected <group[:name]>")
+	}
+
+	b, err := cfg.backend()
+	if err != nil {
+		return err
+	}
+
+	var dependencies []string
+	if parts := strings.SplitN(args[0], ":", 2); len(parts) == 2 {
+		dependencies = []string{args[0]}
+	} else {
+		names, err := b.DependencyNames(args[0])
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			dependencies = append(dependencies, args[0]+":"+name)
+		}
+	}
+
+	var items []storage.StorageDto
+	for _, dependency := range dependencies {
+		usages, err := b.WhoUses(dependency, nil, nil)
+		if err != nil {
+			return err
+		}
+		items = append(items, usages...)
+	}
+
+	drifts := graph.Drifts(items)
+	t := &table{headers: []string{"dependency", "version", "latest", "repos"}}
+	for _, drift := range drifts {
+		for _, usage := range drift.Versions {
+			t.add(drift.Dependency, usage.Version, drift.Latest, strings.Join(usage.Repos, " "))
+		}
+	}
+	return cfg.print(drifts, t)
+}
+
+func runPath(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("path", flag.ExitOnError)
+	filter := filterFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 3 {
+		return fmt.Errorf("expected <org/repo> <ref> <group:name>")
+	}
+
+	b, err := cfg.backend()
+	if err != nil {
+		return err
+	}
+	items, err := b.Dependencies(flags.Arg(0), flags.Arg(1), nonEmpty(filter))
+	if err != nil {
+		return err
+	}
+
+	paths := graph.Paths(items, flags.Arg(2))
+	if paths == nil {
+		return fmt.Errorf("%s is not used by %s/%s", flags.Arg(2), flags.Arg(0), flags.Arg(1))
+	}
+
+	t := &table{headers: []string{"depth", "path"}}
+	for _, path := range paths {
+		t.add(fmt.Sprint(len(path)), strings.Join(path, " -> "))
+	}
+	return cfg.print(paths, t)
+}
+
+func runImpact(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("impact", flag.ExitOnError)
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected <group:name> <version>")
+	}
+
+	b, err := cfg.backend()
+	if err != nil {
+		return err
+	}
+	impacts, err := graph.BlastRadius(b, flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"repo", "ref", "current", "jump", "direct", "via"}}
+	for _, impact := range impacts {
+		jump := impact.Jump
+		if impact.Downgrade {
+			jump += " downgrade"
+		}
+		t.add(impact.Repo, impact.Ref, impact.Current, jump, fmt.Sprint(impact.Direct), strings.Join(impact.Via, " "))
+	}
+	return cfg.print(impacts, t)
+}
+
+func runRepoGraph(cfg *config, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("expected [<org/repo>]")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		edges, err := c.RepoGraph()
+		if err != nil {
+			return err
+		}
+		t := &table{headers: []string{"consumer", "producer", "artifacts"}}
+		for _, edge := range edges {
+			t.add(edge.Consumer, edge.Producer, strings.Join(edge.Artifacts, " "))
+		}
+		return cfg.print(edges, t)
+	}
+
+	links, err := c.RepoLinks(args[0])
+	if err != nil {
+		return err
+	}
+	t := &table{headers: []string{"direction", "repo", "depth", "via", "artifacts"}}
+	for _, link := range links.Upstream {
+		t.add("upstream", link.Repo, fmt.Sprint(link.Depth), link.Via, strings.Join(link.Artifacts, " "))
+	}
+	for _, link := range links.Downstream {
+		t.add("downstream", link.Repo, fmt.Sprint(link.Depth), link.Via, strings.Join(link.Artifacts, " "))
+	}
+	return cfg.print(links, t)
+}
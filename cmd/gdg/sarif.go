@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// runSarif writes SARIF log for code scanning upload, e.g. github/codeql-action/upload-sarif
+func runSarif(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("sarif", flag.ExitOnError)
+	file := flags.String("file", "", "location reported for dependencies declared in unknown file, build.gradle by default")
+	out := flags.String("out", "", "output file, stdout by default")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected <org/repo> <ref>")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	log, err := c.Sarif(flags.Arg(0), flags.Arg(1), *file)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(append(log, '\n'))
+		return err
+	}
+	return ioutil.WriteFile(*out, log, 0644)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/cyclonedx"
+	"gradle-serverless-dependencies-graph/lib/gradle"
+	"gradle-serverless-dependencies-graph/lib/payload"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runUpload(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("upload", flag.ExitOnError)
+	format := flags.String("format", "auto", "input format: auto, json, cyclonedx, lockfile or gradle (output of `gradle dependencies`)")
+	produces := flags.String("produces", "", "comma separated group:name artifacts published by repository")
+	flags.Parse(args)
+
+	if flags.NArg() < 3 {
+		return fmt.Errorf("expected <org/repo> <ref> <file>...")
+	}
+	repo, ref := flags.Arg(0), flags.Arg(1)
+
+	deps, err := readUploads(flags.Args()[2:], *format)
+	if err != nil {
+		return err
+	}
+	if *produces != "" {
+		deps.Produces = append(deps.Produces, strings.Split(*produces, ",")...)
+	}
+	if verr := payload.Validate(deps); verr != nil {
+		for _, v := range verr.Violations {
+			fmt.Fprintf(os.Stderr, "%s.%s %q: %s\n", v.Entry, v.Field, v.Value, v.Message)
+		}
+		return verr
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	result, err := c.Upload(repo, ref, *deps)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range result.Violations {
+		fmt.Fprintf(os.Stderr, "policy %s: %s:%s\n", v.Rule, v.Dependency, v.Version)
+	}
+
+	t := &table{headers: []string{"repo", "ref", "dependencies", "violations", "status"}}
+	t.add(repo, ref, fmt.Sprint(len(deps.Dependencies)), fmt.Sprint(len(result.Violations)), result.Status)
+	return cfg.print(result, t)
+}
+
+// readUploads merges several inputs of upload and policy-check
+func readUploads(paths []string, format string) (*storage.DependenciesRest, error) {
+	var deps storage.DependenciesRest
+	for _, path := range paths {
+		part, err := readUpload(path, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		merge(&deps, part)
+	}
+	return &deps, nil
+}
+
+func readUpload(path string, format string) (*storage.DependenciesRest, error) {
+	if format == "auto" {
+		switch {
+		case strings.HasSuffix(path, ".cdx.json") || filepath.Base(path) == "bom.json":
+			format = "cyclonedx"
+		case strings.HasSuffix(path, ".json"):
+			format = "json"
+		case strings.HasSuffix(filepath.Base(path), ".lockfile"):
+			format = "lockfile"
+		default:
+			format = "gradle"
+		}
+	}
+
+	switch format {
+	case "json":
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return payload.Decode(body)
+	case "cyclonedx", "lockfile", "gradle":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		switch format {
+		case "cyclonedx":
+			return cyclonedx.Parse(file)
+		case "lockfile":
+			deps, err := gradle.ParseLockfile(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, dep := range deps.Dependencies {
+				dep.Location.File = repositoryPath(path)
+			}
+			return deps, nil
+		}
+		return gradle.ParseDependencyReport(file)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// repositoryPath makes path relative to working directory, upload is expected to run from repository root
+func repositoryPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// merge combines several inputs (e.g. lockfiles of all subprojects) into a single payload
+func merge(into *storage.DependenciesRest, part *storage.DependenciesRest) {
+	index := make(map[string]int)
+	for idx, dep := range into.Dependencies {
+		index[dep.Group+":"+dep.Name+":"+dep.Version] = idx
+	}
+	for _, dep := range part.Dependencies {
+		key := dep.Group + ":" + dep.Name + ":" + dep.Version
+		if idx, ok := index[key]; ok && dep.Variant == nil && into.Dependencies[idx].Variant == nil {
+			into.Dependencies[idx].Configurations = append(into.Dependencies[idx].Configurations, dep.Configurations...)
+			continue
+		}
+		index[key] = len(into.Dependencies)
+		into.Dependencies = append(into.Dependencies, dep)
+	}
+
+	into.Plugins = append(into.Plugins, part.Plugins...)
+	into.Edges = append(into.Edges, part.Edges...)
+	into.Produces = append(into.Produces, part.Produces...)
+	if part.Environment != nil {
+		into.Environment = part.Environment
+	}
+}
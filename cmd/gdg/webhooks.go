@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"strings"
+)
+
+func runWebhooks(cfg *config, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected no arguments")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	hooks, err := c.Webhooks()
+	if err != nil {
+		return err
+	}
+	return printWebhooks(cfg, hooks)
+}
+
+func runWebhookAdd(cfg *config, args []string) error {
+	flags := flag.NewFlagSet("webhook-add", flag.ExitOnError)
+	repos := flags.String("repos", "", "comma separated org/repo globs, e.g. acme/*")
+	refs := flags.String("refs", "", "comma separated ref globs, e.g. main,release/*")
+	artifacts := flags.String("artifacts", "", "comma separated group:name globs, e.g. org.apache.logging.log4j:*")
+	description := flags.String("description", "", "description of receiver")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected <url>")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	hook, secret, err := c.CreateWebhook(storage.WebhookDto{
+		Url:         flags.Arg(0),
+		Description: *description,
+		Repos:       splitList(*repos),
+		Refs:        splitList(*refs),
+		Artifacts:   splitList(*artifacts),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Secret is never shown again, receiver needs it to verify X-Gdg-Signature-256
+	t := &table{headers: []string{"id", "url", "secret"}}
+	t.add(hook.Id, hook.Url, secret)
+	return cfg.print(struct {
+		*storage.WebhookDto
+		Secret string `json:"secret"`
+	}{hook, secret}, t)
+}
+
+func runWebhookDelete(cfg *config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected <id>")
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+	return c.DeleteWebhook(args[0])
+}
+
+func printWebhooks(cfg *config, hooks []storage.WebhookDto) error {
+	t := &table{headers: []string{"id", "url", "repos", "refs", "artifacts", "created", "created-by"}}
+	for _, hook := range hooks {
+		t.add(hook.Id, hook.Url, strings.Join(hook.Repos, " "), strings.Join(hook.Refs, " "), strings.Join(hook.Artifacts, " "), hook.Created, hook.CreatedBy)
+	}
+	if hooks == nil {
+		hooks = []storage.WebhookDto{}
+	}
+	return cfg.print(hooks, t)
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
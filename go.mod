@@ -13,6 +13,8 @@ require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/pkg/errors v0.9.1
 	go.uber.org/zap v1.17.0
+	golang.org/x/crypto v0.9.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
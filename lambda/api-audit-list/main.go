@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/authorization"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	auditTableName := os.Getenv("DYNAMODB_TABLE_AUDIT")
+	cfg := storage.StorageConfig{
+		AuditTableName: &auditTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+	logger.Debug("Lambda called",
+		zap.String("requestId", request.RequestContext.RequestID),
+		zap.Reflect("queryStringParameters", request.QueryStringParameters),
+	)
+
+	// Authorizer denies this route for other roles, check again in case policy is misconfigured
+	if helpers.AuthorizerContext(request, "role") != string(authorization.RoleAdmin) {
+		return helpers.ApiError(http.StatusForbidden, "Forbidden"), nil
+	}
+
+	filter := storage.AuditFilter{
+		Principal: request.QueryStringParameters["principal"],
+		Repo:      request.QueryStringParameters["repo"],
+		From:      request.QueryStringParameters["from"],
+		To:        request.QueryStringParameters["to"],
+	}
+	// Stored time is UTC, so offsets of query are normalized
+	for _, value := range []*string{&filter.From, &filter.To} {
+		if *value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, *value)
+		if err != nil {
+			return helpers.ApiError(http.StatusBadRequest, "from and to must be RFC3339 time"), nil
+		}
+		*value = parsed.UTC().Format(storage.AuditTimeLayout)
+	}
+
+	entries, err := storageSvc.ListAudit(request.RequestContext.RequestID, filter)
+	if err != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+	return helpers.ApiResponse(http.StatusOK, entries), nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/authorization"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/policy"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	policiesTableName := os.Getenv("DYNAMODB_TABLE_POLICIES")
+	auditTableName := os.Getenv("DYNAMODB_TABLE_AUDIT")
+	cfg := storage.StorageConfig{
+		PoliciesTableName: &policiesTableName,
+		AuditTableName:    &auditTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+	logger.Debug("Lambda called",
+		zap.String("requestId", request.RequestContext.RequestID),
+		zap.String("routeKey", request.RouteKey),
+	)
+
+	if request.RequestContext.HTTP.Method != http.MethodPut {
+		stored, err := storageSvc.GetPolicy(request.RequestContext.RequestID, storage.DefaultPolicyId)
+		if err != nil && err.Code == storage.ErrObjectNotFound {
+			return helpers.ApiError(http.StatusNotFound, "Policy is not set"), nil
+		}
+		if err != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		return helpers.ApiResponse(http.StatusOK, stored), nil
+	}
+
+	// Authorizer allows writes to api only for admins, check again in case policy is misconfigured
+	if helpers.AuthorizerContext(request, "role") != string(authorization.RoleAdmin) {
+		return helpers.ApiError(http.StatusForbidden, "Forbidden"), nil
+	}
+
+	// Body is policy document itself, API Gateway encodes it when content type is not textual
+	document := request.Body
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return helpers.ApiError(http.StatusBadRequest, "Malformed request body"), nil
+		}
+		document = string(decoded)
+	}
+	if _, err := policy.Parse([]byte(document)); err != nil {
+		return helpers.ApiError(http.StatusUnprocessableEntity, err.Error()), nil
+	}
+
+	principal := helpers.AuthorizerPrincipal(request)
+	dto := storage.PolicyDto{
+		Id:        storage.DefaultPolicyId,
+		Document:  document,
+		Updated:   time.Now().UTC().Format(time.RFC3339),
+		UpdatedBy: principal,
+	}
+	if err := storageSvc.PutPolicy(request.RequestContext.RequestID, dto); err != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+
+	err := storageSvc.PutAudit(request.RequestContext.RequestID, storage.AuditDto{
+		Principal:   principal,
+		Action:      storage.AuditActionPolicyPut,
+		Target:      dto.Id,
+		Count:       1,
+		SourceIp:    request.RequestContext.HTTP.SourceIP,
+		RequestId:   request.RequestContext.RequestID,
+		PayloadHash: helpers.GenerateSHA256(document),
+	})
+	if err != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+
+	logger.Info(fmt.Sprintf("Policy updated by %s", principal))
+	return helpers.ApiResponse(http.StatusOK, dto), nil
+}
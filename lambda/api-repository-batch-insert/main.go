@@ -2,35 +2,67 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/authorization"
+	"gradle-serverless-dependencies-graph/lib/cyclonedx"
+	"gradle-serverless-dependencies-graph/lib/graph"
 	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/payload"
+	"gradle-serverless-dependencies-graph/lib/policy"
 	"gradle-serverless-dependencies-graph/lib/storage"
+	"gradle-serverless-dependencies-graph/lib/webhook"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
+// policyTTL limits how long warm lambda keeps policy and webhooks after they are changed via admin API
+const policyTTL = time.Minute
+
 var (
-	storageSvc *storage.Storage
-	logger     *zap.Logger
+	storageSvc     *storage.Storage
+	logger         *zap.Logger
+	policyCache    *policy.Policy
+	policyLoaded   time.Time
+	webhooksCache  []storage.WebhookDto
+	webhooksLoaded time.Time
 )
 
 type Response struct {
-	Status       string  `json:"status"`
-	UsedCapacity float64 `json:"used-capacity"`
+	Status       string                 `json:"status"`
+	UsedCapacity float64                `json:"used-capacity"`
+	Violations   []storage.ViolationDto `json:"violations,omitempty"`
 }
 
 func init() {
 	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
 	dependenciesTableName := os.Getenv("DYNAMODB_TABLE_DEPENDENCIES")
 	repositoriesTableName := os.Getenv("DYNAMODB_TABLE_REPOSITORIES")
+	auditTableName := os.Getenv("DYNAMODB_TABLE_AUDIT")
+	policiesTableName := os.Getenv("DYNAMODB_TABLE_POLICIES")
+	violationsTableName := os.Getenv("DYNAMODB_TABLE_VIOLATIONS")
+	licensesTableName := os.Getenv("DYNAMODB_TABLE_LICENSES")
+	producersTableName := os.Getenv("DYNAMODB_TABLE_PRODUCERS")
+	webhooksTableName := os.Getenv("DYNAMODB_TABLE_WEBHOOKS")
+	deliveriesTableName := os.Getenv("DYNAMODB_TABLE_DELIVERIES")
 	cfg := storage.StorageConfig{
 		StorageTableName: &storageTableName,
 		DependenciesTableName: &dependenciesTableName,
 		RepositoriesTableName: &repositoriesTableName,
+		AuditTableName: &auditTableName,
+		PoliciesTableName: &policiesTableName,
+		ViolationsTableName: &violationsTableName,
+		LicensesTableName: &licensesTableName,
+		ProducersTableName: &producersTableName,
+		WebhooksTableName: &webhooksTableName,
+		DeliveriesTableName: &deliveriesTableName,
 	}
 
 	logger, _ = helpers.InitLogger("DEBUG", true)
@@ -42,17 +74,68 @@ func main() {
 	lambda.Start(Handler)
 }
 
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+func Handler(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayProxyResponse, error) {
 	defer logger.Sync()
 	logger.Debug("Lambda called",
 		zap.String("requestId", request.RequestContext.RequestID),
-		zap.Reflect("request", request),
+		zap.String("routeKey", request.RouteKey),
+		zap.Reflect("pathParameters", request.PathParameters),
 	)
 
+	if errPath := payload.ValidatePath(request.PathParameters["org"], request.PathParameters["repo"], request.PathParameters["ref"]); errPath != nil {
+		return validationResponse(errPath), nil
+	}
 	repo := fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
 	ref := request.PathParameters["ref"]
-	var deps storage.DependenciesRest
-	json.Unmarshal([]byte(request.Body), &deps)
+
+	// Authorizer policy already limits uploads, check role and scope again in case policy is misconfigured
+	role, scopes := helpers.AuthorizerContext(request, "role"), helpers.AuthorizerContext(request, "scopes")
+	if !authorization.UploadAllowed(role, scopes, repo, ref) {
+		logger.Warn("Upload is not allowed",
+			zap.String("principal", helpers.AuthorizerPrincipal(request)),
+			zap.String("repo", repo),
+			zap.String("ref", ref),
+			zap.String("role", role),
+			zap.String("scopes", scopes),
+		)
+		return helpers.ApiError(http.StatusForbidden, "Forbidden"), nil
+	}
+
+	// API Gateway encodes body when content type is not textual, e.g. CycloneDX
+	body := request.Body
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return helpers.ApiError(http.StatusBadRequest, "Malformed request body"), nil
+		}
+		body = string(decoded)
+	}
+
+	if errSize := payload.ValidateSize([]byte(body)); errSize != nil {
+		return validationResponse(errSize), nil
+	}
+	var deps *storage.DependenciesRest
+	var errDecode error
+	if strings.HasPrefix(request.Headers["content-type"], cyclonedx.ContentType) {
+		deps, errDecode = cyclonedx.Parse(strings.NewReader(body))
+	} else {
+		deps, errDecode = payload.Decode([]byte(body))
+	}
+	if errDecode != nil {
+		logger.Warn("Request data",
+			zap.String("repo", repo),
+			zap.String("branch", ref),
+			zap.Error(errDecode),
+		)
+		return validationResponse(&payload.ValidationError{
+			StatusCode: http.StatusBadRequest,
+			Status:     "Malformed dependencies payload",
+			Violations: []payload.Violation{{Entry: "body", Message: errDecode.Error()}},
+		}), nil
+	}
+	if errValidate := payload.Validate(deps); errValidate != nil {
+		return validationResponse(errValidate), nil
+	}
 
 	logger.Debug("Request data",
 		zap.String("repo", repo),
@@ -60,11 +143,198 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 		zap.Reflect("deps", deps),
 	)
 
-	resp, err := storageSvc.UpsertRepositoryInfo(request.RequestContext.RequestID, repo, ref, deps)
+	produces := helpers.Unique(deps.Produces)
+	if len(produces) > 0 {
+		linked, errLinked := storageSvc.GetProducers(request.RequestContext.RequestID, produces)
+		if errLinked != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		if errProducers := payload.ValidateProducers(repo, produces, linked); errProducers != nil {
+			logger.Warn("Upload rejected, artifacts are produced by another repository",
+				zap.String("principal", helpers.AuthorizerPrincipal(request)),
+				zap.String("repo", repo),
+				zap.Reflect("violations", errProducers.Violations),
+			)
+			return validationResponse(errProducers), nil
+		}
+	}
+
+	// Licenses missing in payload are taken from imported mapping, so policy sees them too
+	licenses.NormalizeDependencies(deps)
+	if unresolved := licenses.Unresolved(*deps); len(unresolved) > 0 {
+		mapped, errLicenses := storageSvc.GetLicenses(request.RequestContext.RequestID, unresolved)
+		if errLicenses != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		licenses.Resolve(deps, mapped)
+	}
 
+	rules, errPolicy := loadPolicy(request.RequestContext.RequestID)
+	if errPolicy != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+	var violations []storage.ViolationDto
+	if rules != nil {
+		violations = rules.Evaluate(repo, ref, *deps)
+	}
+	if len(violations) > 0 && rules.Enforce {
+		logger.Warn("Upload rejected by policy",
+			zap.String("principal", helpers.AuthorizerPrincipal(request)),
+			zap.String("repo", repo),
+			zap.String("ref", ref),
+			zap.Reflect("violations", violations),
+		)
+		return helpers.ApiResponse(http.StatusUnprocessableEntity, Response{Status: "Policy violations", Violations: violations}), nil
+	}
+
+	// Previous state is read before upload overwrites it, only when somebody is subscribed
+	webhooks, errWebhooks := loadWebhooks(request.RequestContext.RequestID)
+	if errWebhooks != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+	var previous []storage.StorageDto
+	if len(webhooks) > 0 {
+		stored, errPrevious := storageSvc.ListDependenciesByRepo(request.RequestContext.RequestID, repo, ref, nil)
+		if errPrevious != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		previous = webhook.LastUpload(*stored)
+	}
+
+	resp, err := storageSvc.UpsertRepositoryInfo(request.RequestContext.RequestID, repo, ref, *deps)
 	if err != nil {
 		return helpers.ApiErrorUnknown(), nil
-	} else {
-		return helpers.ApiResponse(http.StatusOK, Response{Status: "ok", UsedCapacity: resp.UsedCapacity}), nil
 	}
+
+	if len(webhooks) > 0 {
+		// Retried upload has nothing to compare with, so failure is logged instead of failing the upload
+		changes := graph.Diff(previous, webhook.Items(repo, ref, *deps))
+		if errQueue := queueDeliveries(request.RequestContext.RequestID, repo, ref, changes, webhooks); errQueue != nil {
+			logger.Error("Webhook deliveries are not queued",
+				zap.String("requestId", request.RequestContext.RequestID),
+				zap.String("repo", repo),
+				zap.String("ref", ref),
+				zap.Error(errQueue),
+			)
+		}
+	}
+
+	// Upload is idempotent, so client could retry when audit entry is not stored
+	err = storageSvc.PutAudit(request.RequestContext.RequestID, storage.AuditDto{
+		Principal:   helpers.AuthorizerPrincipal(request),
+		Action:      storage.AuditActionUpload,
+		Repo:        repo,
+		Ref:         ref,
+		Count:       len(deps.Dependencies) + len(deps.Plugins),
+		SourceIp:    request.RequestContext.HTTP.SourceIP,
+		RequestId:   request.RequestContext.RequestID,
+		PayloadHash: helpers.GenerateSHA256(body),
+	})
+	if err != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+
+	if len(deps.Produces) > 0 {
+		respProducers, errProducers := storageSvc.PutProducers(request.RequestContext.RequestID, repo, ref, produces)
+		if errProducers != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		resp.UsedCapacity += respProducers.UsedCapacity
+	}
+
+	if rules != nil {
+		respViolations, errViolations := storageSvc.ReplaceViolations(request.RequestContext.RequestID, repo, ref, violations)
+		if errViolations != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		resp.UsedCapacity += respViolations.UsedCapacity
+	}
+
+	return helpers.ApiResponse(http.StatusOK, Response{Status: "ok", UsedCapacity: resp.UsedCapacity, Violations: violations}), nil
+}
+
+// loadPolicy returns policy stored via admin API, nil when there is none
+func loadPolicy(reqId string) (*policy.Policy, error) {
+	if time.Since(policyLoaded) < policyTTL {
+		return policyCache, nil
+	}
+
+	var rules *policy.Policy
+	stored, errStorage := storageSvc.GetPolicy(reqId, storage.DefaultPolicyId)
+	switch {
+	case errStorage == nil:
+		parsed, err := policy.Parse([]byte(stored.Document))
+		if err != nil {
+			logger.Error("Stored policy is malformed", zap.String("requestId", reqId), zap.Error(err))
+			return nil, err
+		}
+		rules = parsed
+	case errStorage.Code != storage.ErrObjectNotFound:
+		return nil, fmt.Errorf("unable to get policy")
+	}
+
+	policyCache, policyLoaded = rules, time.Now()
+	return rules, nil
+}
+
+// loadWebhooks returns subscriptions managed via admin API
+func loadWebhooks(reqId string) ([]storage.WebhookDto, error) {
+	if time.Since(webhooksLoaded) < policyTTL {
+		return webhooksCache, nil
+	}
+
+	stored, errStorage := storageSvc.ListWebhooks(reqId)
+	if errStorage != nil {
+		return nil, fmt.Errorf("unable to list webhooks")
+	}
+
+	webhooksCache, webhooksLoaded = *stored, time.Now()
+	return webhooksCache, nil
+}
+
+// queueDeliveries stores event for every webhook subscribed to changes, dispatcher lambda sends them
+func queueDeliveries(reqId string, repo string, ref string, changes []graph.Change, webhooks []storage.WebhookDto) error {
+	now := time.Now().UTC()
+	event, err := webhook.NewEvent(repo, ref, changes, now)
+	if err != nil || event == nil {
+		return err
+	}
+
+	var deliveries []storage.DeliveryDto
+	for _, hook := range webhooks {
+		filtered := webhook.For(hook, *event)
+		if filtered == nil {
+			continue
+		}
+		body, err := json.Marshal(filtered)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, storage.DeliveryDto{
+			Id:          fmt.Sprintf("%s:%s", event.Id, hook.Id),
+			Webhook:     hook.Id,
+			Repo:        repo,
+			Ref:         ref,
+			Event:       string(body),
+			Status:      storage.DeliveryPending,
+			NextAttempt: now.Format(time.RFC3339),
+			Updated:     now.Format(time.RFC3339),
+			Expires:     now.Add(webhook.Retention).Unix(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if _, errStorage := storageSvc.PutDeliveries(reqId, deliveries); errStorage != nil {
+		return errStorage.Err
+	}
+	return nil
+}
+
+func validationResponse(err *payload.ValidationError) *events.APIGatewayProxyResponse {
+	logger.Warn("Invalid request",
+		zap.Int("status", err.StatusCode),
+		zap.Reflect("violations", err.Violations),
+	)
+	return helpers.ApiResponse(err.StatusCode, err)
 }
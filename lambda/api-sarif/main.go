@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/osv"
+	"gradle-serverless-dependencies-graph/lib/sarif"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+)
+
+var (
+	version    = "dev"
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	advisoriesTableName := os.Getenv("DYNAMODB_TABLE_ADVISORIES")
+	violationsTableName := os.Getenv("DYNAMODB_TABLE_VIOLATIONS")
+	cfg := storage.StorageConfig{
+		StorageTableName:    &storageTableName,
+		AdvisoriesTableName: &advisoriesTableName,
+		ViolationsTableName: &violationsTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	reqId := request.RequestContext.RequestID
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	repo := fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
+	ref := request.PathParameters["ref"]
+
+	items, err := storageSvc.ListDependenciesByRepo(reqId, repo, ref, nil)
+	if err != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+	if len(*items) == 0 {
+		return helpers.ApiErrorNotFound(), nil
+	}
+
+	findings, errScan := osv.Scan(*items, func(pkg string) ([]storage.AdvisoryDto, error) {
+		resp, err := storageSvc.ListAdvisoriesByPackage(reqId, pkg)
+		if err != nil {
+			return nil, err
+		}
+		return *resp, nil
+	})
+	if errScan != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+
+	violations, err := storageSvc.ListViolations(reqId, &repo, &ref)
+	if err != nil {
+		return helpers.ApiErrorUnknown(), nil
+	}
+
+	report := sarif.NewReport(version, *items)
+	if file, ok := request.QueryStringParameters["file"]; ok && file != "" {
+		report.DefaultFile = file
+	}
+	report.AddFindings(findings)
+	report.AddViolations(*violations)
+
+	resp := helpers.ApiResponse(http.StatusOK, report.Log())
+	resp.Headers["Content-Type"] = sarif.ContentType
+	return resp, nil
+}
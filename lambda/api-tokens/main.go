@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/authorization"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+type CreateRequest struct {
+	Name          string   `json:"name"`
+	Role          string   `json:"role"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires-in-days"`
+}
+
+type CreateResponse struct {
+	Token   string   `json:"token"`
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Role    string   `json:"role"`
+	Scopes  []string `json:"scopes,omitempty"`
+	Expires string   `json:"expires"`
+}
+
+func init() {
+	tokensTableName := os.Getenv("DYNAMODB_TABLE_TOKENS")
+	auditTableName := os.Getenv("DYNAMODB_TABLE_AUDIT")
+	cfg := storage.StorageConfig{
+		TokensTableName: &tokensTableName,
+		AuditTableName:  &auditTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+	logger.Debug("Lambda called",
+		zap.String("requestId", request.RequestContext.RequestID),
+		zap.String("routeKey", request.RouteKey),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	owner := helpers.AuthorizerPrincipal(request)
+	if owner == "" {
+		return helpers.ApiError(http.StatusForbidden, "Forbidden"), nil
+	}
+
+	switch request.RequestContext.HTTP.Method {
+	case http.MethodPost:
+		return createToken(request, owner), nil
+	case http.MethodDelete:
+		err := storageSvc.RevokeToken(request.RequestContext.RequestID, request.PathParameters["id"], owner)
+		if err != nil {
+			if err.Code == storage.ErrObjectNotFound {
+				return helpers.ApiErrorNotFound(), nil
+			}
+			return helpers.ApiErrorUnknown(), nil
+		}
+		if err := storageSvc.PutAudit(request.RequestContext.RequestID, auditEntry(request, owner, storage.AuditActionTokenRevoke, request.PathParameters["id"])); err != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		return helpers.ApiErrorNoContent(), nil
+	default:
+		tokens, err := storageSvc.ListTokensByOwner(request.RequestContext.RequestID, owner)
+		if err != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		return helpers.ApiResponse(http.StatusOK, tokens), nil
+	}
+}
+
+func createToken(request events.APIGatewayV2HTTPRequest, owner string) *events.APIGatewayProxyResponse {
+	var req CreateRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil || req.Name == "" {
+		return helpers.ApiError(http.StatusBadRequest, "Token name is required")
+	}
+	expiration, err := authorization.TokenExpiration(req.ExpiresInDays)
+	if err != nil {
+		return helpers.ApiError(http.StatusBadRequest, err.Error())
+	}
+	// Token gets role of its owner unless lower one is requested
+	ownerRole, err := authorization.ParseRole(helpers.AuthorizerContext(request, "role"))
+	if err != nil {
+		return helpers.ApiError(http.StatusForbidden, "Forbidden")
+	}
+	role := ownerRole
+	if req.Role != "" {
+		if role, err = authorization.ParseRole(req.Role); err != nil {
+			return helpers.ApiError(http.StatusBadRequest, err.Error())
+		}
+		if !ownerRole.Includes(role) {
+			return helpers.ApiError(http.StatusForbidden, fmt.Sprintf("Role %s is not allowed", role))
+		}
+	}
+	// Token could not upload outside of owner scopes
+	ownerScopes, _ := authorization.ParseScopes(helpers.AuthorizerContext(request, "scopes"))
+	scopes, err := authorization.ParseScopes(strings.Join(req.Scopes, ","))
+	if err != nil {
+		return helpers.ApiError(http.StatusBadRequest, err.Error())
+	}
+	if len(scopes) == 0 {
+		scopes = ownerScopes
+	}
+	if !authorization.ScopesInclude(ownerScopes, scopes) {
+		return helpers.ApiError(http.StatusForbidden, "Scopes are wider than owner scopes")
+	}
+
+	token, id, secret, err := authorization.GenerateToken()
+	if err != nil {
+		logger.Error("Unable to generate token", zap.Error(err))
+		return helpers.ApiErrorUnknown()
+	}
+	now := time.Now().UTC()
+	dto := storage.TokenDto{
+		Id:      id,
+		Hash:    authorization.HashTokenSecret(secret),
+		Name:    req.Name,
+		Owner:   owner,
+		Role:    string(role),
+		Scopes:  scopes,
+		Created: now.Format(time.RFC3339),
+		Expires: now.Add(expiration).Format(time.RFC3339),
+	}
+	if errStorage := storageSvc.PutToken(request.RequestContext.RequestID, dto); errStorage != nil {
+		return helpers.ApiErrorUnknown()
+	}
+
+	if errStorage := storageSvc.PutAudit(request.RequestContext.RequestID, auditEntry(request, owner, storage.AuditActionTokenCreate, dto.Id)); errStorage != nil {
+		return helpers.ApiErrorUnknown()
+	}
+
+	// Token is shown only once, so response is not passed through helpers.ApiResponse which logs body
+	body, _ := json.Marshal(CreateResponse{
+		Token:   token,
+		Id:      dto.Id,
+		Name:    dto.Name,
+		Role:    dto.Role,
+		Scopes:  dto.Scopes,
+		Expires: dto.Expires,
+	})
+	logger.Info("Token created",
+		zap.String("requestId", request.RequestContext.RequestID),
+		zap.Int("status", http.StatusCreated),
+		zap.String("id", dto.Id),
+	)
+	return &events.APIGatewayProxyResponse{
+		StatusCode: http.StatusCreated,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
+
+func auditEntry(request events.APIGatewayV2HTTPRequest, owner string, action string, tokenId string) storage.AuditDto {
+	return storage.AuditDto{
+		Principal: owner,
+		Action:    action,
+		Target:    tokenId,
+		Count:     1,
+		SourceIp:  request.RequestContext.HTTP.SourceIP,
+		RequestId: request.RequestContext.RequestID,
+	}
+}
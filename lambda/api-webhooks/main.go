@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/authorization"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"gradle-serverless-dependencies-graph/lib/webhook"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+type CreateRequest struct {
+	Url         string   `json:"url"`
+	Description string   `json:"description"`
+	Repos       []string `json:"repos"`
+	Refs        []string `json:"refs"`
+	Artifacts   []string `json:"artifacts"`
+}
+
+type CreateResponse struct {
+	storage.WebhookDto
+	Secret string `json:"secret"`
+}
+
+func init() {
+	webhooksTableName := os.Getenv("DYNAMODB_TABLE_WEBHOOKS")
+	auditTableName := os.Getenv("DYNAMODB_TABLE_AUDIT")
+	cfg := storage.StorageConfig{
+		WebhooksTableName: &webhooksTableName,
+		AuditTableName:    &auditTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+	logger.Debug("Lambda called",
+		zap.String("requestId", request.RequestContext.RequestID),
+		zap.String("routeKey", request.RouteKey),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	// Webhooks receive dependencies of every subscribed repository, so only admins manage them
+	if helpers.AuthorizerContext(request, "role") != string(authorization.RoleAdmin) {
+		return helpers.ApiError(http.StatusForbidden, "Forbidden"), nil
+	}
+	principal := helpers.AuthorizerPrincipal(request)
+
+	switch request.RequestContext.HTTP.Method {
+	case http.MethodPost:
+		return createWebhook(request, principal), nil
+	case http.MethodDelete:
+		err := storageSvc.DeleteWebhook(request.RequestContext.RequestID, request.PathParameters["id"])
+		if err != nil {
+			if err.Code == storage.ErrObjectNotFound {
+				return helpers.ApiErrorNotFound(), nil
+			}
+			return helpers.ApiErrorUnknown(), nil
+		}
+		if err := storageSvc.PutAudit(request.RequestContext.RequestID, auditEntry(request, principal, storage.AuditActionWebhookDelete, request.PathParameters["id"])); err != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		return helpers.ApiErrorNoContent(), nil
+	default:
+		webhooks, err := storageSvc.ListWebhooks(request.RequestContext.RequestID)
+		if err != nil {
+			return helpers.ApiErrorUnknown(), nil
+		}
+		return helpers.ApiResponse(http.StatusOK, webhooks), nil
+	}
+}
+
+func createWebhook(request events.APIGatewayV2HTTPRequest, principal string) *events.APIGatewayProxyResponse {
+	var req CreateRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil || req.Url == "" {
+		return helpers.ApiError(http.StatusBadRequest, "Webhook url is required")
+	}
+	target, err := url.Parse(req.Url)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return helpers.ApiError(http.StatusBadRequest, "Webhook url must be absolute http(s) url")
+	}
+
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		logger.Error("Unable to generate webhook id", zap.Error(err))
+		return helpers.ApiErrorUnknown()
+	}
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		logger.Error("Unable to generate webhook secret", zap.Error(err))
+		return helpers.ApiErrorUnknown()
+	}
+	dto := storage.WebhookDto{
+		Id:          hex.EncodeToString(idBytes),
+		Url:         req.Url,
+		Secret:      secret,
+		Description: req.Description,
+		Repos:       helpers.Unique(req.Repos),
+		Refs:        helpers.Unique(req.Refs),
+		Artifacts:   helpers.Unique(req.Artifacts),
+		Created:     time.Now().UTC().Format(time.RFC3339),
+		CreatedBy:   principal,
+	}
+	if err := webhook.Validate(dto); err != nil {
+		return helpers.ApiError(http.StatusBadRequest, err.Error())
+	}
+	if errStorage := storageSvc.PutWebhook(request.RequestContext.RequestID, dto); errStorage != nil {
+		return helpers.ApiErrorUnknown()
+	}
+
+	if errStorage := storageSvc.PutAudit(request.RequestContext.RequestID, auditEntry(request, principal, storage.AuditActionWebhookCreate, dto.Id)); errStorage != nil {
+		return helpers.ApiErrorUnknown()
+	}
+
+	// Secret is shown only once, so response is not passed through helpers.ApiResponse which logs body
+	body, _ := json.Marshal(CreateResponse{WebhookDto: dto, Secret: dto.Secret})
+	logger.Info("Webhook created",
+		zap.String("requestId", request.RequestContext.RequestID),
+		zap.Int("status", http.StatusCreated),
+		zap.String("id", dto.Id),
+	)
+	return &events.APIGatewayProxyResponse{
+		StatusCode: http.StatusCreated,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
+
+func auditEntry(request events.APIGatewayV2HTTPRequest, principal string, action string, webhookId string) storage.AuditDto {
+	return storage.AuditDto{
+		Principal: principal,
+		Action:    action,
+		Target:    webhookId,
+		Count:     1,
+		SourceIp:  request.RequestContext.HTTP.SourceIP,
+		RequestId: request.RequestContext.RequestID,
+	}
+}
@@ -7,18 +7,33 @@ import (
 	"go.uber.org/zap"
 	"gradle-serverless-dependencies-graph/lib/authorization"
 	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
 	"os"
 	"strings"
+	"time"
 )
 
 var (
 	logger           *zap.Logger
 	authorizationSvc *authorization.Authorization
+	oidcSvc          *authorization.OIDC
 )
 
 func init() {
+	tokensTableName := os.Getenv("DYNAMODB_TABLE_TOKENS")
+	cfg := storage.StorageConfig{
+		TokensTableName: &tokensTableName,
+	}
+
 	logger, _ = helpers.InitLogger("DEBUG", true)
-	authorizationSvc, _ = authorization.NewAuthorization(logger)
+	storageSvc, _ := storage.NewStorage(cfg, logger)
+	authorizationSvc, _ = authorization.NewAuthorization(logger, storageSvc)
+	oidcCfg, err := authorization.OIDCConfigFromEnv()
+	if err != nil {
+		logger.Error("OIDC is not enabled", zap.Error(err))
+	} else if oidcCfg != nil {
+		oidcSvc = authorization.NewOIDC(*oidcCfg)
+	}
 }
 
 func main() {
@@ -37,44 +52,75 @@ func Handler(request APIGatewayAuthorizerRequest) (*APIGatewayAuthorizerResponse
 		zap.Reflect("request.RequestContext", request.RequestContext),
 		zap.Reflect("request.PathParameters", request.PathParameters),
 		zap.Reflect("request.StageVariables", request.StageVariables),
-		zap.Strings("request.IdentitySource.md5", helpers.GenerateMD5List(request.IdentitySource)),
 	)
 
-	region := os.Getenv("AWS_REGION")
-	resourceArn := []string{
-		fmt.Sprintf("arn:aws:execute-api:%s:%s:%s/%s/GET/*",
-			region,
-			request.RequestContext.AccountID,
-			request.RequestContext.ApiId,
-			request.RequestContext.Stage,
-		),
-		fmt.Sprintf("arn:aws:execute-api:%s:%s:%s/%s/PUT/*",
-			region,
-			request.RequestContext.AccountID,
-			request.RequestContext.ApiId,
-			request.RequestContext.Stage,
-		),
-	}
-
-
 	if len(request.IdentitySource) >= 1 {
 		identitySource := strings.Split(request.IdentitySource[0], " ")
-		logger.Debug("Authorization to check",
-			zap.Strings("identitySource.IdentitySource.split.md5", helpers.GenerateMD5List(identitySource)),
-		)
-		if len(identitySource) == 2 {
+		if len(identitySource) == 2 && identitySource[0] == "Bearer" && authorization.IsJwt(identitySource[1]) {
+			if oidcSvc == nil {
+				logger.Debug("OIDC is not configured")
+			} else if claims, err := oidcSvc.Verify(identitySource[1], time.Now()); err != nil {
+				logger.Warn("JWT rejected",
+					zap.String("reqId", request.RequestContext.RequestID),
+					zap.Error(err),
+				)
+			} else {
+				// Pipeline could only upload its own repository and ref
+				principal := fmt.Sprintf("oidc:%s", claims.Subject)
+				role := authorization.RoleUploader
+				scopes := []string{claims.Scope()}
+				resp := allowPolicy(request, principal, role.Routes(scopes), append(role.DeniedRoutes(), authorization.TokensRoutes()...))
+				resp.Context = map[string]interface{}{
+					"principal": principal,
+					"role":      string(role),
+					"scopes":    strings.Join(scopes, ","),
+				}
+				logger.Debug("Response",
+					zap.Reflect("resp", resp),
+				)
+				return resp, nil
+			}
+		} else if len(identitySource) == 2 && identitySource[0] == "Bearer" {
+			if token, ok := authorizationSvc.CheckToken(request.RequestContext.RequestID, identitySource[1], time.Now()); ok {
+				role := authorization.TokenRole(token)
+				// Tokens could be managed only by users, not by other tokens
+				resp := allowPolicy(request, token.Owner, role.Routes(token.Scopes), append(role.DeniedRoutes(), authorization.TokensRoutes()...))
+				resp.Context = map[string]interface{}{
+					"principal": token.Owner,
+					"role":      string(role),
+					"scopes":    strings.Join(token.Scopes, ","),
+					"token":     token.Id,
+				}
+				logger.Debug("Response",
+					zap.Reflect("resp", resp),
+				)
+				return resp, nil
+			}
+		} else if len(identitySource) == 2 {
 			authEncodedBytes, err := base64.StdEncoding.DecodeString(identitySource[1])
 			if err != nil {
 				return nil, err
 			}
 			authEncoded := string(authEncodedBytes)
-			logger.Debug("Authorization Info to check",
-				zap.String("authEncoded.md5", helpers.GenerateMD5(authEncoded)),
-			)
 
-			authInfo := strings.Split(authEncoded, ":")
+			authInfo := strings.SplitN(authEncoded, ":", 2)
 			if len(authInfo) == 2 && authorizationSvc.CheckCredentials(request.RequestContext.RequestID, authInfo[0], authInfo[1]) {
-				resp := generatePolicy(authInfo[0],"Allow",resourceArn)
+				role := authorization.UserRole(authInfo[0])
+				scopes, errScopes := authorization.UserScopes(authInfo[0])
+				if errScopes != nil {
+					logger.Error("Malformed user scopes, uploads are not allowed",
+						zap.String("user", authInfo[0]),
+						zap.Error(errScopes),
+					)
+					role = authorization.RoleReader
+				}
+				routes := append(role.Routes(scopes), authorization.TokensRoutes()...)
+				resp := allowPolicy(request, authInfo[0], routes, role.DeniedRoutes())
+				resp.Context = map[string]interface{}{
+					"principal": authInfo[0],
+					"role":      string(role),
+					"scopes":    strings.Join(scopes, ","),
+				}
 				logger.Debug("Response",
 					zap.Reflect("resp", resp),
 				)
@@ -82,13 +128,45 @@ func Handler(request APIGatewayAuthorizerRequest) (*APIGatewayAuthorizerResponse
 			}
 		}
 	}
-	resp := generatePolicy("", "Deny", resourceArn)
+	resp := generatePolicy("", "Deny", []string{executeApiArn(request, "*", "*")})
 	logger.Debug("Response",
 		zap.Reflect("resp", resp),
 	)
 	return resp, nil
 }
 
+// allowPolicy allows routes, explicit deny overrides wide allowed routes like GET /*
+func allowPolicy(request APIGatewayAuthorizerRequest, principalId string, allow []authorization.Route, deny []authorization.Route) *APIGatewayAuthorizerResponse {
+	resp := generatePolicy(principalId, "Allow", executeApiArns(request, allow))
+	if len(deny) > 0 {
+		resp.PolicyDocument.Statement = append(resp.PolicyDocument.Statement, IAMPolicyStatement{
+			Action:   []string{"execute-api:Invoke"},
+			Effect:   "Deny",
+			Resource: executeApiArns(request, deny),
+		})
+	}
+	return resp
+}
+
+func executeApiArns(request APIGatewayAuthorizerRequest, routes []authorization.Route) []string {
+	arns := make([]string, 0, len(routes))
+	for _, route := range routes {
+		arns = append(arns, executeApiArn(request, route.Method, route.Path))
+	}
+	return arns
+}
+
+func executeApiArn(request APIGatewayAuthorizerRequest, method string, path string) string {
+	return fmt.Sprintf("arn:aws:execute-api:%s:%s:%s/%s/%s/%s",
+		os.Getenv("AWS_REGION"),
+		request.RequestContext.AccountID,
+		request.RequestContext.ApiId,
+		request.RequestContext.Stage,
+		method,
+		path,
+	)
+}
+
 func generatePolicy(principalId string, effect string, resourceArn []string) *APIGatewayAuthorizerResponse {
 	return &APIGatewayAuthorizerResponse{
 		PrincipalID: principalId,
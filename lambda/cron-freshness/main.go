@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/freshness"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/outdated"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// writeReserve is left from lambda deadline to store snapshots measured so far
+const writeReserve = 30 * time.Second
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+	resolver   *outdated.Resolver
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	metadataTableName := os.Getenv("DYNAMODB_TABLE_METADATA")
+	freshnessTableName := os.Getenv("DYNAMODB_TABLE_FRESHNESS")
+	cfg := storage.StorageConfig{
+		StorageTableName:   &storageTableName,
+		MetadataTableName:  &metadataTableName,
+		FreshnessTableName: &freshnessTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+
+	if mirrorUrl := os.Getenv("MAVEN_MIRROR_URL"); mirrorUrl != "" {
+		mirror, err := maven.NewMirror(mirrorUrl)
+		if err != nil {
+			logger.Error("maven mirror disabled", zap.Error(err))
+			return
+		}
+		resolver = outdated.NewResolver(mirror, storageSvc)
+		if hours, err := strconv.Atoi(os.Getenv("METADATA_TTL_HOURS")); err == nil && hours > 0 {
+			resolver.TTL = time.Duration(hours) * time.Hour
+		}
+	}
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+// Handler takes daily freshness snapshot of every repo/ref, triggered by schedule
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	defer logger.Sync()
+	logger.Info("lambda called", zap.String("eventId", event.ID), zap.Time("time", event.Time))
+
+	if resolver == nil {
+		logger.Warn("MAVEN_MIRROR_URL is not configured, skipping freshness snapshot")
+		return nil
+	}
+
+	byRef := map[string][]storage.StorageDto{}
+	errScan := storageSvc.ScanStorage(event.ID, func(item storage.StorageDto) error {
+		key := fmt.Sprintf("%s:%s", item.Repo, item.Ref)
+		byRef[key] = append(byRef[key], item)
+		return nil
+	})
+	if errScan != nil {
+		return errScan.Err
+	}
+
+	keys := make([]string, 0, len(byRef))
+	for key := range byRef {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ctxResolve := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctxResolve, cancel = context.WithDeadline(ctx, deadline.Add(-writeReserve))
+		defer cancel()
+	}
+
+	now := time.Now()
+	var snapshots []storage.FreshnessDto
+	for _, key := range keys {
+		items := byRef[key]
+		if err := resolver.Annotate(ctxResolve, event.ID, items, now); err != nil {
+			logger.Error("Outdated annotation failed", zap.String("ref", key), zap.Error(err))
+			continue
+		}
+		// Partially annotated repo/ref would look fresher than it is, metadata fetched so far is cached for next run
+		if ctxResolve.Err() != nil {
+			logger.Warn("Freshness snapshot incomplete", zap.Int("measured", len(snapshots)), zap.Int("total", len(keys)))
+			break
+		}
+		snapshots = append(snapshots, freshness.Measure(items[0].Repo, items[0].Ref, items, now))
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+	if _, errPut := storageSvc.PutFreshness(event.ID, snapshots); errPut != nil {
+		return errPut.Err
+	}
+	logger.Info("Freshness snapshot stored", zap.Int("count", len(snapshots)))
+	return nil
+}
@@ -17,6 +17,9 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 <html><body><pre>
 <a href="/dependency/">Dependencies</a>
 <a href="/repositories/">Repositories</a>
+<a href="/plugin/">Plugins</a>
+<a href="/platform/">Platforms</a>
+<a href="/environment/">Build environment</a>
 </pre></body></html>
 `
 	return helpers.HtmlResponse(http.StatusOK, &resp), nil
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/conflicts"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/policy"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	policiesTableName := os.Getenv("DYNAMODB_TABLE_POLICIES")
+	cfg := storage.StorageConfig{
+		StorageTableName:  &storageTableName,
+		PoliciesTableName: &policiesTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	reqId := request.RequestContext.RequestID
+	logger.Info("lambda called",
+		zap.String("reqId", reqId),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	rules, err := loadRules(reqId)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := request.QueryStringParameters["rule"]; ok {
+		var filtered []conflicts.Rule
+		for _, rule := range rules {
+			if rule.Id == id {
+				filtered = append(filtered, rule)
+			}
+		}
+		rules = filtered
+	}
+
+	data := struct {
+		Repo  string
+		Ref   string
+		Rules []conflicts.Rule
+		Items []conflicts.Conflict
+	}{Rules: rules}
+
+	var items []storage.StorageDto
+	if _, ok := request.PathParameters["org"]; ok {
+		data.Repo = fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
+		data.Ref = request.PathParameters["ref"]
+		resp, err := storageSvc.ListDependenciesByRepo(reqId, data.Repo, data.Ref, nil)
+		if err != nil {
+			return nil, err
+		}
+		items = *resp
+	} else {
+		// There is no index of artifacts by repo/ref, so whole storage is scanned
+		errScan := storageSvc.ScanStorage(reqId, func(item storage.StorageDto) error {
+			if item.Kind == "" || item.Kind == storage.KindLibrary {
+				items = append(items, item)
+			}
+			return nil
+		})
+		if errScan != nil {
+			return nil, errScan
+		}
+	}
+	data.Items = conflicts.Detect(rules, items)
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, data.Items), nil
+	}
+
+	text := Template
+	if data.Repo != "" {
+		text = RefTemplate
+	}
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(text); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
+
+// loadRules returns built-in conflict rules merged with ones of policy stored via admin API
+func loadRules(reqId string) ([]conflicts.Rule, error) {
+	stored, errStorage := storageSvc.GetPolicy(reqId, storage.DefaultPolicyId)
+	switch {
+	case errStorage == nil:
+		parsed, err := policy.Parse([]byte(stored.Document))
+		if err != nil {
+			logger.Error("Stored policy is malformed", zap.String("requestId", reqId), zap.Error(err))
+			return nil, err
+		}
+		return conflicts.Rules(parsed.Conflicts), nil
+	case errStorage.Code != storage.ErrObjectNotFound:
+		return nil, fmt.Errorf("unable to get policy")
+	}
+	return conflicts.Rules(nil), nil
+}
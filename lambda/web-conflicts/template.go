@@ -0,0 +1,29 @@
+package main
+
+var Template = `
+<html><body><pre>
+Dependency conflicts, artifacts providing the same classes or competing implementations:
+{{range .Items}}
+<a href="/conflict/{{.Repo}}/{{.Ref}}">{{.Repo}} {{.Ref}}</a> <a href="/conflict?rule={{.Rule}}">{{.Rule}}</a>{{if .Variants}} variants{{range .Variants}} {{.}}{{end}}{{end}}{{range .Families}} {{.Family}}:{{range .Artifacts}} {{.}}{{end}};{{end}}
+{{else}}
+No conflicts found
+{{end}}
+rules:
+{{range .Rules}}
+<a href="/conflict?rule={{.Id}}">{{.Id}}</a> {{.Description}}
+{{end}}
+</pre></body></html>
+`
+
+var RefTemplate = `
+<html><body><pre>
+<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}} {{.Ref}}</a> (<a href="/conflict">all conflicts</a>)
+{{range .Items}}
+warning: {{.Rule}}{{if .Description}} - {{.Description}}{{end}}{{if .Variants}}, variants{{range .Variants}} {{.}}{{end}}{{end}}
+{{range .Families}}  {{.Family}}:{{range .Artifacts}} {{.}}{{end}}
+{{end}}
+{{else}}
+No conflicts found
+{{end}}
+</pre></body></html>
+`
@@ -45,7 +45,8 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 
 	logger.Info("lambda called",
 		zap.String("reqId", request.RequestContext.RequestID),
-		zap.Reflect("request", request),
+		zap.String("resource", request.Resource),
+		zap.Reflect("pathParameters", request.PathParameters),
 	)
 
 	var parent *string
@@ -61,6 +62,10 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 		return nil,err
 	}
 
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, resp), nil
+	}
+
 	data := struct {
 		Items []storage.DependencyDto
 	}{
@@ -8,29 +8,52 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"go.uber.org/zap"
 	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/outdated"
 	"gradle-serverless-dependencies-graph/lib/storage"
 	"net/http"
 	"os"
+	"strconv"
 	"text/template"
+	"time"
 )
 
+// outdatedTimeout bounds time spent on Maven mirror, dependencies are shown unannotated when exceeded
+const outdatedTimeout = 3 * time.Second
+
 var (
 	storageSvc *storage.Storage
 	logger     *zap.Logger
+	resolver   *outdated.Resolver
 )
 
 func init() {
 	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
 	dependenciesTableName := os.Getenv("DYNAMODB_TABLE_DEPENDENCIES")
 	repositoriesTableName := os.Getenv("DYNAMODB_TABLE_REPOSITORIES")
+	metadataTableName := os.Getenv("DYNAMODB_TABLE_METADATA")
 	cfg := storage.StorageConfig{
 		StorageTableName:      &storageTableName,
 		DependenciesTableName: &dependenciesTableName,
 		RepositoriesTableName: &repositoriesTableName,
+		MetadataTableName:     &metadataTableName,
 	}
 
 	logger, _ = helpers.InitLogger("DEBUG", true)
 	storageSvc, _ = storage.NewStorage(cfg, logger)
+
+	// Outdated annotation is optional, enabled by configured mirror
+	if mirrorUrl := os.Getenv("MAVEN_MIRROR_URL"); mirrorUrl != "" {
+		mirror, err := maven.NewMirror(mirrorUrl)
+		if err != nil {
+			logger.Error("maven mirror disabled", zap.Error(err))
+			return
+		}
+		resolver = outdated.NewResolver(mirror, storageSvc)
+		if hours, err := strconv.Atoi(os.Getenv("METADATA_TTL_HOURS")); err == nil && hours > 0 {
+			resolver.TTL = time.Duration(hours) * time.Hour
+		}
+	}
 }
 
 func main() {
@@ -45,26 +68,43 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 
 	logger.Info("lambda called",
 		zap.String("reqId", request.RequestContext.RequestID),
-		zap.Reflect("request", request),
+		zap.String("resource", request.Resource),
+		zap.Reflect("pathParameters", request.PathParameters),
 	)
 
 	repo := fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
 	ref := request.PathParameters["ref"]
 
-	resp, err := storageSvc.ListDependenciesByRepo(reqId, repo, ref)
+	filter := storage.NewStorageFilter(request.QueryStringParameters)
+
+	resp, err := storageSvc.ListDependenciesByRepo(reqId, repo, ref, filter)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if resolver != nil {
+		ctxOutdated, cancel := context.WithTimeout(ctx, outdatedTimeout)
+		if errOutdated := resolver.Annotate(ctxOutdated, reqId, *resp, time.Now()); errOutdated != nil {
+			logger.Error("outdated annotation failed", zap.String("reqId", reqId), zap.Error(errOutdated))
+		}
+		cancel()
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, resp), nil
+	}
+
 	data := struct {
-		Items []storage.StorageDto
-		Repo  string
-		Ref   string
+		Items  []storage.StorageDto
+		Repo   string
+		Ref    string
+		Filter *storage.StorageFilter
 	}{
-		Items: *resp,
-		Repo:  repo,
-		Ref:   ref,
+		Items:  *resp,
+		Repo:   repo,
+		Ref:    ref,
+		Filter: filter,
 	}
 
 	var tpl *template.Template
@@ -2,9 +2,21 @@ package main
 
 var Template = `
 <html><body><pre>
-{{.Repo}}/{{.Ref}}:
-{{range .Items}}
-{{.Dependency}}:{{.Version}}
-{{end}}
+{{.Repo}}/{{.Ref}}:{{with .Filter}} (variant={{.Variant}} build-type={{.BuildType}} flavor={{.Flavor}}){{end}} <a href="/vulnerability/{{.Repo}}/{{.Ref}}">vulnerabilities</a> <a href="/violation/{{.Repo}}/{{.Ref}}">violations</a> <a href="/license/{{.Repo}}/{{.Ref}}">licenses</a> <a href="/conflict/{{.Repo}}/{{.Ref}}">conflicts</a>
+{{range .Items}}{{if or (eq .Kind "") (eq .Kind "library")}}
+{{.Dependency}}:{{.Version}}{{if .Variant}} <a href="?variant={{.Variant}}">[{{.Variant}}]</a>{{end}}{{if .ManagedBy}} (managed by {{.ManagedBy}}{{if .Overrides}}, overrides {{.ManagedVersion}}{{end}}){{end}}{{with .Outdated}}{{if .Behind}} latest {{.Latest}}, {{.Behind}} behind{{end}}{{if .AgeDays}} ({{.AgeDays}} days old){{end}}{{end}}
+{{end}}{{end}}
+platforms:
+{{range .Items}}{{if eq .Kind "platform"}}
+<a href="/platform/{{.Dependency}}">{{.Dependency}}</a>:{{.Version}} ({{.Platform}}){{with .Outdated}}{{if .Behind}} latest {{.Latest}}, {{.Behind}} behind{{end}}{{end}}
+{{end}}{{end}}
+plugins:
+{{range .Items}}{{if eq .Kind "plugin"}}
+<a href="/plugin/{{.Dependency}}">{{.Dependency}}</a>:{{.Version}}
+{{end}}{{end}}
+environment:
+{{range .Items}}{{if eq .Kind "environment"}}
+<a href="/environment">{{.Tool}}</a>:{{.Version}}
+{{end}}{{end}}
 </pre></body></html>
 `
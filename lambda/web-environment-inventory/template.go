@@ -0,0 +1,12 @@
+package main
+
+var Template = `
+<html><body><pre>
+{{range .Items}}
+{{.Tool}}:
+{{range $version, $repos := .Versions}}  {{$version}}:
+{{range $repos}}    <a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a>
+{{end}}{{else}}  No items found
+{{end}}{{end}}
+</pre></body></html>
+`
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/freshness"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+// AllOrgs is id of trend summarizing every org
+const AllOrgs = "*"
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	freshnessTableName := os.Getenv("DYNAMODB_TABLE_FRESHNESS")
+	cfg := storage.StorageConfig{
+		FreshnessTableName: &freshnessTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.Reflect("pathParameters", request.PathParameters),
+		zap.Reflect("queryStringParameters", request.QueryStringParameters),
+	)
+
+	if _, ok := request.PathParameters["org"]; ok {
+		return history(request)
+	}
+
+	resp, err := storageSvc.ListFreshness(request.RequestContext.RequestID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := freshness.Dashboard{Org: request.QueryStringParameters["org"], Orgs: freshness.Orgs(*resp)}
+	snapshots := *resp
+	if data.Org != "" {
+		snapshots = nil
+		for _, snapshot := range *resp {
+			if freshness.Org(snapshot.Repo) == data.Org {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	trendId := AllOrgs
+	if data.Org != "" {
+		trendId = data.Org
+	}
+	data.Refs = freshness.Latest(snapshots)
+	data.Trend = freshness.Trend(trendId, snapshots)
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, data), nil
+	}
+	return render(Template, data)
+}
+
+// history shows snapshots of single repo/ref
+func history(request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	repo := fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
+	ref := request.PathParameters["ref"]
+
+	resp, err := storageSvc.ListFreshness(request.RequestContext.RequestID, &repo, &ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, resp), nil
+	}
+	return render(HistoryTemplate, struct {
+		Repo  string
+		Ref   string
+		Items []storage.FreshnessDto
+	}{repo, ref, *resp})
+}
+
+func render(text string, data interface{}) (*events.APIGatewayProxyResponse, error) {
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Funcs(template.FuncMap{
+		"percent": func(share float64) string { return fmt.Sprintf("%.0f%%", share*100) },
+	}).Parse(text); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
@@ -0,0 +1,33 @@
+package main
+
+var Template = `
+<html><body><pre>
+Dependency freshness{{if .Org}} of {{.Org}} (<a href="/freshness">all orgs</a>){{end}}, libyears is total time between releases of used and latest versions:
+
+orgs:
+{{range .Orgs}}
+<a href="/freshness?org={{.Id}}">{{.Id}}</a>: {{printf "%.1f" .Libyears}} libyears, {{percent .CurrentShare}} current ({{.Current}}/{{.Resolved}}, {{.Dependencies}} dependencies) on {{.Date}}
+{{else}}
+No snapshots yet
+{{end}}
+refs:
+{{range .Refs}}
+<a href="/freshness/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a>: {{printf "%.1f" .Libyears}} libyears, {{percent .CurrentShare}} current ({{.Current}}/{{.Resolved}}) on {{.Date}}
+{{end}}
+trend:
+{{range .Trend}}
+{{.Date}}: {{printf "%.1f" .Libyears}} libyears, {{percent .CurrentShare}} current ({{.Current}}/{{.Resolved}})
+{{end}}
+</pre></body></html>
+`
+
+var HistoryTemplate = `
+<html><body><pre>
+<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> dependency freshness (<a href="/freshness">dashboard</a>):
+{{range .Items}}
+{{.Date}}: {{printf "%.1f" .Libyears}} libyears, {{percent .CurrentShare}} current ({{.Current}}/{{.Resolved}}, {{.Dependencies}} dependencies)
+{{else}}
+No snapshots yet
+{{end}}
+</pre></body></html>
+`
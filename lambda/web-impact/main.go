@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+// storageSource adapts storage to graph.Source for single request
+type storageSource struct {
+	reqId string
+}
+
+func (s storageSource) Dependencies(repo string, ref string, filter *storage.StorageFilter) ([]storage.StorageDto, error) {
+	resp, err := storageSvc.ListDependenciesByRepo(s.reqId, repo, ref, filter)
+	if err != nil {
+		return nil, err.Err
+	}
+	return *resp, nil
+}
+
+func (s storageSource) WhoUses(dependency string, version *string, filter *storage.StorageFilter) ([]storage.StorageDto, error) {
+	resp, err := storageSvc.ListRepositoriesByDependency(s.reqId, dependency, version, filter)
+	if err != nil {
+		return nil, err.Err
+	}
+	return *resp, nil
+}
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	cfg := storage.StorageConfig{
+		StorageTableName: &storageTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	dependency := fmt.Sprintf("%s:%s", request.PathParameters["group"], request.PathParameters["name"])
+	target := request.PathParameters["version"]
+
+	impacts, err := graph.BlastRadius(storageSource{reqId: request.RequestContext.RequestID}, dependency, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, impacts), nil
+	}
+
+	data := struct {
+		Dependency string
+		Target     string
+		Items      []graph.Impact
+	}{
+		Dependency: dependency,
+		Target:     target,
+		Items:      impacts,
+	}
+
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(Template); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
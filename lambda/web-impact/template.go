@@ -0,0 +1,13 @@
+package main
+
+var Template = `
+<html><body><pre>
+Upgrade of {{.Dependency}} to {{.Target}} affects {{len .Items}} refs:
+{{range .Items}}
+<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a>: {{.Current}}{{range .Versions}} [{{.}}]{{end}} -> {{$.Target}} {{if .Downgrade}}downgrade{{else if .Jump}}{{.Jump}}{{else}}unchanged{{end}}{{if .Direct}} direct{{end}}{{if .Via}} via{{range .Via}} {{.}}{{end}}{{end}}{{if gt (len .Path) 1}}
+  {{range $idx, $dep := .Path}}{{if $idx}} -> {{end}}{{$dep}}{{end}}{{end}}
+{{else}}
+No repositories use {{.Dependency}}
+{{end}}
+</pre></body></html>
+`
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"net/url"
+	"os"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	cfg := storage.StorageConfig{
+		StorageTableName: &storageTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	reqId := request.RequestContext.RequestID
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	data := struct {
+		Repo    string
+		Ref     string
+		License string
+		Items   []licenses.Usage
+	}{}
+
+	if _, ok := request.PathParameters["org"]; ok {
+		data.Repo = fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
+		data.Ref = request.PathParameters["ref"]
+		resp, err := storageSvc.ListDependenciesByRepo(reqId, data.Repo, data.Ref, storage.NewStorageFilter(request.QueryStringParameters))
+		if err != nil {
+			return nil, err
+		}
+		data.Items = licenses.Inventory(*resp)
+	} else {
+		// There is no index by license, whole storage is scanned like export does
+		var items []storage.StorageDto
+		err := storageSvc.ScanStorage(reqId, func(item storage.StorageDto) error {
+			if item.Kind == storage.KindLibrary || item.Kind == storage.KindPlatform {
+				items = append(items, item)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if license, ok := request.PathParameters["license"]; ok {
+			if unescaped, errUnescape := url.PathUnescape(license); errUnescape == nil {
+				license = unescaped
+			}
+			data.License = license
+			data.Items = licenses.Inventory(items, license)
+		} else {
+			data.Items = licenses.Inventory(items)
+		}
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, data.Items), nil
+	}
+
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(Template); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
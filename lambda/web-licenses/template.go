@@ -0,0 +1,22 @@
+package main
+
+var Template = `
+<html><body><pre>
+{{if .Repo}}<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> licenses:
+{{range .Items}}
+<a href="/license/{{.License}}">{{.License}}</a> ({{.Category}}):{{range .Dependencies}}
+  {{.Dependency}}:{{.Version}}{{end}}
+{{else}}
+No dependencies found
+{{end}}{{else if .License}}Repositories using {{.License}}:
+{{range .Items}}
+{{.License}} ({{.Category}}):{{range .Dependencies}}
+  <a href="/license/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> {{.Dependency}}:{{.Version}}{{end}}
+{{else}}
+No repositories found
+{{end}}{{else}}Licenses:
+{{range .Items}}
+<a href="/license/{{.License}}">{{.License}}</a> ({{.Category}}) {{len .Repos}} refs, {{len .Dependencies}} dependencies{{end}}
+{{end}}
+</pre></body></html>
+`
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/smithy-go/ptr"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	dependenciesTableName := os.Getenv("DYNAMODB_TABLE_DEPENDENCIES")
+	repositoriesTableName := os.Getenv("DYNAMODB_TABLE_REPOSITORIES")
+	cfg := storage.StorageConfig{
+		StorageTableName:      &storageTableName,
+		DependenciesTableName: &dependenciesTableName,
+		RepositoriesTableName: &repositoriesTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	reqId := request.RequestContext.RequestID
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.String("resource", request.Resource),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	data := struct {
+		Platform  string
+		Platforms []storage.DependencyDto
+		Items     []storage.PlatformUsageDto
+	}{}
+
+	group, okGroup := request.PathParameters["group"]
+	name, okName := request.PathParameters["name"]
+
+	if okGroup && okName {
+		data.Platform = fmt.Sprintf("%s:%s", group, name)
+		resp, err := storageSvc.ListPlatformUsage(reqId, data.Platform, storage.NewStorageFilter(request.QueryStringParameters))
+		if err != nil {
+			return nil, err
+		}
+		data.Items = *resp
+	} else {
+		resp, err := storageSvc.ListDependenciesByParent(reqId, ptr.String(storage.PlatformParent))
+		if err != nil {
+			return nil, err
+		}
+		data.Platforms = *resp
+	}
+
+	if helpers.WantsJson(request) {
+		if data.Platform != "" {
+			return helpers.ApiResponse(http.StatusOK, data.Items), nil
+		}
+		return helpers.ApiResponse(http.StatusOK, data.Platforms), nil
+	}
+
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(Template); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
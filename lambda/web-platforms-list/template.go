@@ -0,0 +1,18 @@
+package main
+
+var Template = `
+<html><body><pre>
+{{if .Platform}}<a href="/platform">../</a>
+{{.Platform}}:
+{{range .Items}}
+<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> {{.Version}} ({{.Platform}}, manages {{len .Managed}})
+{{range .Overrides}}  overrides {{.Dependency}}: {{.ManagedVersion}} -> {{.Version}}
+{{end}}{{else}}
+No items found
+{{end}}{{else}}{{range .Platforms}}
+<a href="/platform/{{.Child}}">{{.Child}}</a>
+{{else}}
+No items found
+{{end}}{{end}}
+</pre></body></html>
+`
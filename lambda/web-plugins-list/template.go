@@ -0,0 +1,17 @@
+package main
+
+var Template = `
+<html><body><pre>
+{{if .Plugin}}<a href="/plugin">../</a>
+{{.Plugin}}:
+{{range .Items}}
+<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> {{.Version}} ({{.Marker}})
+{{else}}
+No items found
+{{end}}{{else}}{{range .Plugins}}
+<a href="/plugin/{{.Child}}">{{.Child}}</a>
+{{else}}
+No items found
+{{end}}{{end}}
+</pre></body></html>
+`
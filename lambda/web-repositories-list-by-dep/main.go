@@ -45,29 +45,37 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*event
 
 	logger.Info("lambda called",
 		zap.String("reqId", request.RequestContext.RequestID),
-		zap.Reflect("request", request),
+		zap.String("resource", request.Resource),
+		zap.Reflect("pathParameters", request.PathParameters),
 	)
 
-	var parent *string
-	org, okOrg := request.PathParameters["org"]
-	repo, okRepo := request.PathParameters["repo"]
+	group := request.PathParameters["group"]
+	name := request.PathParameters["name"]
+	dependency := fmt.Sprintf("%s:%s", group, name)
 
-	if okOrg && okRepo {
-		parent = ptr.String(fmt.Sprintf("%s/%s", org, repo))
-	} else {
-		parent = nil
+	var version *string
+	if val, ok := request.PathParameters["version"]; ok {
+		version = ptr.String(val)
 	}
 
-	resp, err := storageSvc.ListRepositoriesByParent(reqId, parent)
+	resp, err := storageSvc.ListRepositoriesByDependency(reqId, dependency, version, storage.NewStorageFilter(request.QueryStringParameters))
 
 	if err != nil {
-		return nil,err
+		return nil, err
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, resp), nil
 	}
 
 	data := struct {
-		Items []storage.RepositoryDto
+		Items      []storage.StorageDto
+		Dependency string
+		Version    string
 	}{
-		Items: *resp,
+		Items:      *resp,
+		Dependency: dependency,
+		Version:    ptr.ToString(version),
 	}
 
 	var tpl *template.Template
@@ -2,8 +2,11 @@ package main
 
 var Template = `
 <html><body><pre>
-{{range items}}
-<a href="/repository/{{.Child}}">{{.Child}}</a>
-{{end}
+{{.Dependency}}{{if .Version}}:{{.Version}}{{end}}:
+{{range .Items}}
+<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> {{.Version}}{{if .Variant}} [{{.Variant}}]{{end}}
+{{else}}
+No items found
+{{end}}
 </pre></body></html>
 `
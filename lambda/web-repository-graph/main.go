@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+	prefixes   map[string]string
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	producersTableName := os.Getenv("DYNAMODB_TABLE_PRODUCERS")
+	cfg := storage.StorageConfig{
+		StorageTableName:   &storageTableName,
+		ProducersTableName: &producersTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+
+	var err error
+	if prefixes, err = graph.ParsePrefixes(os.Getenv("PRODUCER_PREFIXES")); err != nil {
+		logger.Error("PRODUCER_PREFIXES ignored", zap.Error(err))
+	}
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	reqId := request.RequestContext.RequestID
+	logger.Info("lambda called",
+		zap.String("reqId", reqId),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	declared, err := storageSvc.ListProducers(reqId)
+	if err != nil {
+		return nil, err
+	}
+	producers := graph.NewProducers(*declared, prefixes)
+
+	// There is no index of internal artifacts usages, so whole storage is scanned
+	var items []storage.StorageDto
+	errScan := storageSvc.ScanStorage(reqId, func(item storage.StorageDto) error {
+		if _, ok := producers.Repo(item.Dependency); ok {
+			items = append(items, item)
+		}
+		return nil
+	})
+	if errScan != nil {
+		return nil, errScan
+	}
+	edges := graph.RepoGraph(items, producers)
+
+	if _, ok := request.PathParameters["org"]; !ok {
+		if helpers.WantsJson(request) {
+			return helpers.ApiResponse(http.StatusOK, edges), nil
+		}
+		return render(Template, struct{ Edges []graph.RepoEdge }{edges})
+	}
+
+	data := graph.RepoLinks{
+		Repo: fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"]),
+	}
+	data.Upstream = graph.Upstream(edges, data.Repo)
+	data.Downstream = graph.Downstream(edges, data.Repo)
+	for _, producer := range *declared {
+		if producer.Repo == data.Repo {
+			data.Artifacts = append(data.Artifacts, producer.Artifact)
+		}
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, data), nil
+	}
+	return render(RepoTemplate, data)
+}
+
+func render(text string, data interface{}) (*events.APIGatewayProxyResponse, error) {
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(text); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
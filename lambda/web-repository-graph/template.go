@@ -0,0 +1,27 @@
+package main
+
+var Template = `
+<html><body><pre>
+Repositories depending on internal artifacts of other repositories:
+{{range .Edges}}
+<a href="/repository-graph/{{.Consumer}}">{{.Consumer}}</a> -> <a href="/repository-graph/{{.Producer}}">{{.Producer}}</a>{{range .Artifacts}} {{.}}{{end}}
+{{else}}
+No links found, declare "produces" in uploads or configure producer prefixes
+{{end}}
+</pre></body></html>
+`
+
+var RepoTemplate = `
+<html><body><pre>
+<a href="/repository/{{.Repo}}">{{.Repo}}</a> (<a href="/repository-graph">all repositories</a>){{if .Artifacts}} publishes{{range .Artifacts}} {{.}}{{end}}{{end}}
+
+upstream:
+{{range .Upstream}}
+{{.Depth}} <a href="/repository-graph/{{.Repo}}">{{.Repo}}</a>{{if .Via}} via {{.Via}}{{end}}{{range .Artifacts}} {{.}}{{end}}
+{{end}}
+downstream:
+{{range .Downstream}}
+{{.Depth}} <a href="/repository-graph/{{.Repo}}">{{.Repo}}</a>{{if .Via}} via {{.Via}}{{end}}{{range .Artifacts}} {{.}}{{end}}
+{{end}}
+</pre></body></html>
+`
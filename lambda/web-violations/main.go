@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"sort"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	violationsTableName := os.Getenv("DYNAMODB_TABLE_VIOLATIONS")
+	cfg := storage.StorageConfig{
+		ViolationsTableName: &violationsTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	data := struct {
+		Repo  string
+		Ref   string
+		Items []storage.ViolationDto
+	}{}
+
+	var repo, ref *string
+	if _, ok := request.PathParameters["org"]; ok {
+		data.Repo = fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
+		data.Ref = request.PathParameters["ref"]
+		repo, ref = &data.Repo, &data.Ref
+	}
+	resp, err := storageSvc.ListViolations(request.RequestContext.RequestID, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	data.Items = *resp
+	if rule, ok := request.QueryStringParameters["rule"]; ok {
+		var filtered []storage.ViolationDto
+		for _, item := range data.Items {
+			if item.Rule == rule {
+				filtered = append(filtered, item)
+			}
+		}
+		data.Items = filtered
+	}
+	sort.Slice(data.Items, func(i, j int) bool {
+		if data.Items[i].Id != data.Items[j].Id {
+			return data.Items[i].Id < data.Items[j].Id
+		}
+		return data.Items[i].Key < data.Items[j].Key
+	})
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, data.Items), nil
+	}
+
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(Template); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
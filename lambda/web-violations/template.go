@@ -0,0 +1,13 @@
+package main
+
+var Template = `
+<html><body><pre>
+{{if .Repo}}<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> policy violations:{{else}}Policy violations:{{end}}
+{{range .Items}}
+{{if not $.Repo}}<a href="/violation/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> {{end}}{{.Dependency}}:{{.Version}} <a href="/violation?rule={{.Rule}}">{{.Rule}}</a>{{range .Configurations}} {{.}}{{end}}{{range .Licenses}} <a href="/license/{{.}}">{{.}}</a>{{end}}{{if .Description}}
+  {{.Description}}{{end}}
+{{else}}
+No violations found
+{{end}}
+</pre></body></html>
+`
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/osv"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+)
+
+func init() {
+	storageTableName := os.Getenv("DYNAMODB_TABLE_STORAGE")
+	advisoriesTableName := os.Getenv("DYNAMODB_TABLE_ADVISORIES")
+	cfg := storage.StorageConfig{
+		StorageTableName:    &storageTableName,
+		AdvisoriesTableName: &advisoriesTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (*events.APIGatewayProxyResponse, error) {
+	defer logger.Sync()
+
+	reqId := request.RequestContext.RequestID
+
+	logger.Info("lambda called",
+		zap.String("reqId", request.RequestContext.RequestID),
+		zap.Reflect("pathParameters", request.PathParameters),
+	)
+
+	data := struct {
+		Repo     string
+		Ref      string
+		Advisory string
+		Items    []osv.Finding
+	}{}
+
+	lookup := func(pkg string) ([]storage.AdvisoryDto, error) {
+		resp, err := storageSvc.ListAdvisoriesByPackage(reqId, pkg)
+		if err != nil {
+			return nil, err
+		}
+		return *resp, nil
+	}
+
+	var errScan error
+	if id, ok := request.PathParameters["id"]; ok {
+		data.Advisory = id
+		data.Items, errScan = affectedRepositories(reqId, id, lookup)
+	} else {
+		data.Repo = fmt.Sprintf("%s/%s", request.PathParameters["org"], request.PathParameters["repo"])
+		data.Ref = request.PathParameters["ref"]
+		resp, err := storageSvc.ListDependenciesByRepo(reqId, data.Repo, data.Ref, storage.NewStorageFilter(request.QueryStringParameters))
+		if err != nil {
+			return nil, err
+		}
+		data.Items, errScan = osv.Scan(*resp, lookup)
+	}
+	if errScan != nil {
+		return nil, errScan
+	}
+
+	if helpers.WantsJson(request) {
+		return helpers.ApiResponse(http.StatusOK, data.Items), nil
+	}
+
+	var tpl *template.Template
+	var errTpl error
+	if tpl, errTpl = template.New("html").Parse(Template); errTpl != nil {
+		return nil, errTpl
+	}
+
+	var contentIO bytes.Buffer
+	if errTpl = tpl.Execute(&contentIO, data); errTpl != nil {
+		return nil, errTpl
+	}
+
+	content := contentIO.String()
+
+	return helpers.HtmlResponse(http.StatusOK, &content), nil
+}
+
+// affectedRepositories resolves advisory id or alias (CVE) to packages and matches every stored version of them
+func affectedRepositories(reqId string, id string, lookup osv.Lookup) ([]osv.Finding, error) {
+	aliases, err := lookup(storage.AliasPrefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []osv.Finding
+	for _, alias := range aliases {
+		onlyAdvisory := func(pkg string) ([]storage.AdvisoryDto, error) {
+			advisories, err := lookup(pkg)
+			if err != nil {
+				return nil, err
+			}
+			var filtered []storage.AdvisoryDto
+			for _, advisory := range advisories {
+				if advisory.Id == alias.Id {
+					filtered = append(filtered, advisory)
+				}
+			}
+			return filtered, nil
+		}
+
+		for _, pkg := range alias.Packages {
+			resp, errStorage := storageSvc.ListRepositoriesByDependency(reqId, pkg, nil, nil)
+			if errStorage != nil {
+				return nil, errStorage
+			}
+			findings, err := osv.Scan(*resp, onlyAdvisory)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, findings...)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,18 @@
+package main
+
+var Template = `
+<html><body><pre>
+{{if .Advisory}}{{.Advisory}} affects:
+{{range .Items}}
+<a href="/vulnerability/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> {{.Dependency}}:{{.Version}} {{.Severity}}
+{{else}}
+No affected repositories found
+{{end}}{{else}}<a href="/repository/{{.Repo}}/{{.Ref}}">{{.Repo}}/{{.Ref}}</a> vulnerabilities:
+{{range .Items}}
+{{.Dependency}}:{{.Version}} <a href="/advisory/{{.Advisory}}">{{.Advisory}}</a> {{range .Aliases}}{{.}} {{end}}{{.Severity}}
+  {{.Summary}}
+{{else}}
+No vulnerabilities found
+{{end}}{{end}}
+</pre></body></html>
+`
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"gradle-serverless-dependencies-graph/lib/webhook"
+	"os"
+	"time"
+)
+
+// sendReserve is left from lambda deadline to send delivery with retries and store its outcome
+const sendReserve = 45 * time.Second
+
+var (
+	storageSvc *storage.Storage
+	logger     *zap.Logger
+	sender     *webhook.Sender
+)
+
+func init() {
+	webhooksTableName := os.Getenv("DYNAMODB_TABLE_WEBHOOKS")
+	deliveriesTableName := os.Getenv("DYNAMODB_TABLE_DELIVERIES")
+	cfg := storage.StorageConfig{
+		WebhooksTableName:   &webhooksTableName,
+		DeliveriesTableName: &deliveriesTableName,
+	}
+
+	logger, _ = helpers.InitLogger("DEBUG", true)
+	storageSvc, _ = storage.NewStorage(cfg, logger)
+	sender = webhook.NewSender()
+}
+
+func main() {
+	lambda.Start(Handler)
+}
+
+// Handler sends deliveries inserted into deliveries table, stream batch has Records. Scheduled event has none,
+// then pending deliveries due for retry are sent
+func Handler(ctx context.Context, event events.DynamoDBEvent) error {
+	defer logger.Sync()
+
+	var ctxId string
+	var deliveries []storage.DeliveryDto
+	if len(event.Records) == 0 {
+		ctxId = time.Now().UTC().Format(time.RFC3339)
+		logger.Info("lambda called by schedule")
+
+		due, err := storageSvc.ListDueDeliveries(ctxId, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return err.Err
+		}
+		deliveries = *due
+	} else {
+		ctxId = event.Records[0].EventID
+		logger.Info("lambda called by stream", zap.Int("records", len(event.Records)))
+
+		for _, record := range event.Records {
+			if record.EventName != string(events.DynamoDBOperationTypeInsert) {
+				continue
+			}
+			delivery, err := storageSvc.GetDelivery(ctxId, record.Change.Keys["Id"].String())
+			if err != nil && err.Code == storage.ErrObjectNotFound {
+				continue
+			}
+			if err != nil {
+				return err.Err
+			}
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	stored, errWebhooks := storageSvc.ListWebhooks(ctxId)
+	if errWebhooks != nil {
+		return errWebhooks.Err
+	}
+	webhooks := map[string]storage.WebhookDto{}
+	for _, hook := range *stored {
+		webhooks[hook.Id] = hook
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	for i, delivery := range deliveries {
+		// Deliveries left pending are sent by next scheduled run
+		if hasDeadline && time.Until(deadline) < sendReserve {
+			logger.Warn("lambda deadline is close, deliveries postponed", zap.Int("postponed", len(deliveries)-i))
+			break
+		}
+		if delivery.Status != storage.DeliveryPending {
+			continue
+		}
+		deliver(ctx, ctxId, delivery, webhooks)
+	}
+	return nil
+}
+
+// deliver sends single delivery and stores outcome, failed one gets NextAttempt until webhook.MaxAttempts
+func deliver(ctx context.Context, ctxId string, delivery storage.DeliveryDto, webhooks map[string]storage.WebhookDto) {
+	delivery.Attempts++
+
+	var err error
+	hook, ok := webhooks[delivery.Webhook]
+	if ok {
+		err = sender.Send(ctx, hook.Url, hook.Secret, delivery.Id, []byte(delivery.Event))
+	} else {
+		err = errors.New("webhook is deleted")
+	}
+
+	now := time.Now().UTC()
+	var deliveryErr *webhook.DeliveryError
+	switch {
+	case err == nil:
+		delivery.Status, delivery.LastError, delivery.NextAttempt = storage.DeliveryDelivered, "", ""
+	case !ok || (errors.As(err, &deliveryErr) && deliveryErr.Permanent) || delivery.Attempts >= webhook.MaxAttempts:
+		delivery.Status, delivery.LastError, delivery.NextAttempt = storage.DeliveryFailed, err.Error(), ""
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttempt = webhook.NextAttempt(delivery.Attempts, now).Format(time.RFC3339)
+	}
+	delivery.Updated = now.Format(time.RFC3339)
+	delivery.Expires = now.Add(webhook.Retention).Unix()
+
+	logger.Info("webhook delivery",
+		zap.String("id", delivery.Id),
+		zap.String("repo", delivery.Repo),
+		zap.String("ref", delivery.Ref),
+		zap.String("status", delivery.Status),
+		zap.Int("attempts", delivery.Attempts),
+		zap.String("error", delivery.LastError),
+	)
+
+	// Not stored outcome keeps delivery pending, so it is sent again by schedule and receiver may get it twice
+	if errStorage := storageSvc.UpdateDelivery(ctxId, delivery); errStorage != nil {
+		logger.Error("Delivery outcome is not stored", zap.String("id", delivery.Id), zap.Error(errStorage.Err))
+	}
+}
@@ -0,0 +1,150 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"time"
+)
+
+// Archive is JSON Lines stream, optionally gzip compressed: header line followed by one record per table item
+//
+//	{"format":"gradle-dependencies-graph","version":1,"created":"2021-09-01T10:00:00Z"}
+//	{"table":"storage","item":{"dependency":"com.google.guava:guava","version":"32.1.2-jre",...}}
+//	{"table":"tokens","item":{"Id":{"S":"2b7e..."},"Owner":{"S":"ci"},...}}
+//
+// Versions: 1 - initial, 2 - storage items carry their "id" key, 3 - raw items of other tables.
+// Readers accept all known versions.
+const (
+	Format  = "gradle-dependencies-graph"
+	Version = 3
+)
+
+const (
+	TableStorage      = "storage"
+	TableDependencies = "dependencies"
+	TableRepositories = "repositories"
+)
+
+// Tables stored as raw Item, webhook deliveries are transient and never exported
+const (
+	TableTokens     = "tokens"
+	TableAudit      = "audit"
+	TableAdvisories = "advisories"
+	TablePolicies   = "policies"
+	TableViolations = "violations"
+	TableLicenses   = "licenses"
+	TableMetadata   = "metadata"
+	TableFreshness  = "freshness"
+	TableProducers  = "producers"
+	TableWebhooks   = "webhooks"
+)
+
+type Header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Created string `json:"created"`
+}
+
+type Record struct {
+	Table string          `json:"table"`
+	Item  json.RawMessage `json:"item"`
+}
+
+func (r Record) Decode(item interface{}) error {
+	return json.Unmarshal(r.Item, item)
+}
+
+type Writer struct {
+	gzip    *gzip.Writer
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+	Count   map[string]int
+}
+
+func NewWriter(out io.Writer, compress bool) (*Writer, error) {
+	w := &Writer{Count: make(map[string]int)}
+	if compress {
+		w.gzip = gzip.NewWriter(out)
+		out = w.gzip
+	}
+	w.buffer = bufio.NewWriter(out)
+	w.encoder = json.NewEncoder(w.buffer)
+
+	header := Header{
+		Format:  Format,
+		Version: Version,
+		Created: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := w.encoder.Encode(header); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) Write(table string, item interface{}) error {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	w.Count[table]++
+	return w.encoder.Encode(Record{Table: table, Item: raw})
+}
+
+func (w *Writer) Close() error {
+	if err := w.buffer.Flush(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+type Reader struct {
+	Header  Header
+	decoder *json.Decoder
+}
+
+// NewReader checks archive header, gzip compression is detected automatically
+func NewReader(in io.Reader) (*Reader, error) {
+	buffered := bufio.NewReader(in)
+	magic, err := buffered.Peek(2)
+	if err != nil {
+		return nil, errors.Wrap(err, "empty archive")
+	}
+
+	var source io.Reader = buffered
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		if source, err = gzip.NewReader(buffered); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Reader{decoder: json.NewDecoder(source)}
+	if err := r.decoder.Decode(&r.Header); err != nil {
+		return nil, errors.Wrap(err, "malformed archive header")
+	}
+	if r.Header.Format != Format {
+		return nil, fmt.Errorf("unknown archive format %q", r.Header.Format)
+	}
+	if r.Header.Version < 1 || r.Header.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d, expected up to %d", r.Header.Version, Version)
+	}
+	return r, nil
+}
+
+// Next returns io.EOF when archive is over
+func (r *Reader) Next() (*Record, error) {
+	var record Record
+	if err := r.decoder.Decode(&record); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "malformed archive record")
+	}
+	return &record, nil
+}
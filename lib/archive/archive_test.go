@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"bytes"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		var buffer bytes.Buffer
+		w, err := NewWriter(&buffer, compress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(TableStorage, storage.StorageDto{Dependency: "com.google.guava:guava", Version: "32.1.2-jre", Repo: "acme/app", Ref: "main"})
+		w.Write(TableRepositories, storage.RepositoryDto{Parent: storage.RootParent, Child: "acme/app"})
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if w.Count[TableStorage] != 1 || w.Count[TableRepositories] != 1 {
+			t.Error("Wrong counters", w.Count)
+		}
+
+		r, err := NewReader(&buffer)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+		if r.Header.Version != Version {
+			t.Error("Wrong header", r.Header)
+		}
+
+		record, err := r.Next()
+		if err != nil || record.Table != TableStorage {
+			t.Fatal("Wrong record", record, err)
+		}
+		var dto storage.StorageDto
+		if err := record.Decode(&dto); err != nil || dto.Repo != "acme/app" || dto.Version != "32.1.2-jre" {
+			t.Error("Wrong item", dto, err)
+		}
+
+		if record, err = r.Next(); err != nil || record.Table != TableRepositories {
+			t.Fatal("Wrong record", record, err)
+		}
+		if _, err = r.Next(); err != io.EOF {
+			t.Error("EOF expected", err)
+		}
+	}
+}
+
+func TestReaderRejectsUnknownVersion(t *testing.T) {
+	if _, err := NewReader(strings.NewReader(`{"format":"gradle-dependencies-graph","version":99}`)); err == nil {
+		t.Error("Error is nil")
+	}
+	if _, err := NewReader(strings.NewReader(`{"format":"something-else","version":1}`)); err == nil {
+		t.Error("Error is nil")
+	}
+}
+
+func TestReaderAcceptsPreviousVersions(t *testing.T) {
+	r, err := NewReader(strings.NewReader(`{"format":"gradle-dependencies-graph","version":1}` + "\n"))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Error("EOF expected", err)
+	}
+}
+
+func TestItemRoundTrip(t *testing.T) {
+	attrs := map[string]types.AttributeValue{
+		"Id":      &types.AttributeValueMemberS{Value: "2b7e151628aed2a6"},
+		"Expires": &types.AttributeValueMemberN{Value: "1700000000"},
+		"Revoked": &types.AttributeValueMemberBOOL{Value: false},
+		"Scopes":  &types.AttributeValueMemberSS{Value: []string{"acme/*", "acme/app@main"}},
+		"Empty":   &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+		"Nested": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"Events": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "upload"},
+				&types.AttributeValueMemberNULL{Value: true},
+			}},
+			"Secret": &types.AttributeValueMemberB{Value: []byte{0, 1, 2}},
+		}},
+	}
+
+	var buffer bytes.Buffer
+	w, _ := NewWriter(&buffer, false)
+	item, err := EncodeItem(attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(TableTokens, item)
+	w.Close()
+
+	r, _ := NewReader(&buffer)
+	record, err := r.Next()
+	if err != nil || record.Table != TableTokens {
+		t.Fatal("Wrong record", record, err)
+	}
+	var decoded Item
+	if err := record.Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	result, err := DecodeItem(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(attrs, result) {
+		t.Errorf("Wrong item %#v", result)
+	}
+
+	if _, err := DecodeItem(Item{"Id": {}}); err == nil {
+		t.Error("Error is nil")
+	}
+}
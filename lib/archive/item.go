@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Item is raw table item in DynamoDB JSON, used for tables without archive specific model, so hidden
+// attributes like token hashes survive export, e.g. {"Id":{"S":"2b7e..."},"Scopes":{"SS":["acme/*"]}}
+type Item map[string]Value
+
+type Value struct {
+	S    *string  `json:"S,omitempty"`
+	N    *string  `json:"N,omitempty"`
+	B    []byte   `json:"B,omitempty"`
+	BOOL *bool    `json:"BOOL,omitempty"`
+	NULL *bool    `json:"NULL,omitempty"`
+	SS   []string `json:"SS,omitempty"`
+	NS   []string `json:"NS,omitempty"`
+	BS   [][]byte `json:"BS,omitempty"`
+	L    *[]Value `json:"L,omitempty"`
+	M    *Item    `json:"M,omitempty"`
+}
+
+// EncodeItem converts DynamoDB item to archive item
+func EncodeItem(attrs map[string]types.AttributeValue) (Item, error) {
+	item := make(Item, len(attrs))
+	for name, attr := range attrs {
+		value, err := encodeValue(attr)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		item[name] = value
+	}
+	return item, nil
+}
+
+// DecodeItem converts archive item back to DynamoDB item
+func DecodeItem(item Item) (map[string]types.AttributeValue, error) {
+	attrs := make(map[string]types.AttributeValue, len(item))
+	for name, value := range item {
+		attr, err := decodeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		attrs[name] = attr
+	}
+	return attrs, nil
+}
+
+func encodeValue(attr types.AttributeValue) (Value, error) {
+	switch v := attr.(type) {
+	case *types.AttributeValueMemberS:
+		return Value{S: &v.Value}, nil
+	case *types.AttributeValueMemberN:
+		return Value{N: &v.Value}, nil
+	case *types.AttributeValueMemberB:
+		return Value{B: v.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return Value{BOOL: &v.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return Value{NULL: &v.Value}, nil
+	case *types.AttributeValueMemberSS:
+		return Value{SS: v.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return Value{NS: v.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return Value{BS: v.Value}, nil
+	case *types.AttributeValueMemberL:
+		list := make([]Value, 0, len(v.Value))
+		for _, member := range v.Value {
+			value, err := encodeValue(member)
+			if err != nil {
+				return Value{}, err
+			}
+			list = append(list, value)
+		}
+		return Value{L: &list}, nil
+	case *types.AttributeValueMemberM:
+		members, err := EncodeItem(v.Value)
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{M: &members}, nil
+	}
+	return Value{}, fmt.Errorf("unsupported attribute type %T", attr)
+}
+
+func decodeValue(value Value) (types.AttributeValue, error) {
+	switch {
+	case value.S != nil:
+		return &types.AttributeValueMemberS{Value: *value.S}, nil
+	case value.N != nil:
+		return &types.AttributeValueMemberN{Value: *value.N}, nil
+	case value.B != nil:
+		return &types.AttributeValueMemberB{Value: value.B}, nil
+	case value.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *value.BOOL}, nil
+	case value.NULL != nil:
+		return &types.AttributeValueMemberNULL{Value: *value.NULL}, nil
+	case value.SS != nil:
+		return &types.AttributeValueMemberSS{Value: value.SS}, nil
+	case value.NS != nil:
+		return &types.AttributeValueMemberNS{Value: value.NS}, nil
+	case value.BS != nil:
+		return &types.AttributeValueMemberBS{Value: value.BS}, nil
+	case value.L != nil:
+		list := make([]types.AttributeValue, 0, len(*value.L))
+		for _, member := range *value.L {
+			attr, err := decodeValue(member)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, attr)
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case value.M != nil:
+		members, err := DecodeItem(*value.M)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: members}, nil
+	}
+	return nil, fmt.Errorf("empty attribute value")
+}
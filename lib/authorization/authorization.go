@@ -1,8 +1,12 @@
 package authorization
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 	"gradle-serverless-dependencies-graph/lib/helpers"
 	"os"
 	"strings"
@@ -10,32 +14,53 @@ import (
 
 type Authorization struct {
 	logger *zap.Logger
+	tokens TokenStore
 }
 
-func NewAuthorization(logger *zap.Logger) (*Authorization, error){
+func NewAuthorization(logger *zap.Logger, tokens TokenStore) (*Authorization, error){
 	return &Authorization{
 		logger: logger,
+		tokens: tokens,
 	}, nil
 }
 
+// CredentialsEnvName returns env var name holding bcrypt hash of user password
+func CredentialsEnvName(username string) string {
+	hash := sha256.Sum256([]byte(username))
+	return fmt.Sprintf("USER_%s", strings.ToUpper(hex.EncodeToString(hash[:])))
+}
+
+// LegacyCredentialsEnvName returns env var name holding unsalted md5 of user password, kept for migration only
+func LegacyCredentialsEnvName(username string) string {
+	return fmt.Sprintf("USER_%s", strings.ToUpper(helpers.GenerateMD5(username)))
+}
+
+// HashPassword returns salted bcrypt hash to be stored in CredentialsEnvName env var
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
 func (svc *Authorization) CheckCredentials(reqId string, username string, password string) bool{
 	svc.logger.Debug("Checking credentials",
 		zap.String("reqId", reqId),
 		zap.String("user", username),
 	)
-	envVarName := fmt.Sprintf("USER_%s", strings.ToUpper(helpers.GenerateMD5(username)))
-	svc.logger.Debug("Looking to env var",
-		zap.String("env var name", envVarName),
-	)
-	if pass,found := os.LookupEnv(envVarName); found {
-		svc.logger.Debug("Env var found",
-			zap.String("env var name", envVarName),
-			zap.String("env var value", pass),
-			)
-		if pass == helpers.GenerateMD5(password) {
-			return true
-		}
+
+	if hash, found := os.LookupEnv(CredentialsEnvName(username)); found {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	}
+
+	if hash, found := os.LookupEnv(LegacyCredentialsEnvName(username)); found {
+		svc.logger.Warn("User has legacy md5 credentials, please migrate to password_hash",
+			zap.String("reqId", reqId),
+			zap.String("user", username),
+		)
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(helpers.GenerateMD5(password))) == 1
 	}
+
 	return false
 }
-
@@ -0,0 +1,125 @@
+package authorization
+
+import (
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"testing"
+)
+
+func TestCheckCredentials(t *testing.T) {
+	hash, err := HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(CredentialsEnvName("ci"), hash)
+	t.Setenv(LegacyCredentialsEnvName("old"), helpers.GenerateMD5("legacy"))
+	svc, _ := NewAuthorization(zap.NewNop(), nil)
+
+	cases := []struct {
+		user     string
+		password string
+		expected bool
+	}{
+		{"ci", "s3cret", true},
+		{"ci", "wrong", false},
+		{"old", "legacy", true},
+		{"old", "wrong", false},
+		{"unknown", "s3cret", false},
+	}
+	for _, c := range cases {
+		if actual := svc.CheckCredentials("test", c.user, c.password); actual != c.expected {
+			t.Errorf("CheckCredentials(%s, %s) = %v", c.user, c.password, actual)
+		}
+	}
+}
+
+func TestRoles(t *testing.T) {
+	if !RoleAdmin.Includes(RoleUploader) || !RoleUploader.Includes(RoleReader) || RoleReader.Includes(RoleUploader) {
+		t.Error("Wrong roles hierarchy")
+	}
+	if Role("").Includes(Role("")) || RoleAdmin.Includes(Role("root")) {
+		t.Error("Unknown role is included")
+	}
+	if _, err := ParseRole("root"); err == nil {
+		t.Error("Unknown role parsed")
+	}
+	if role, _ := ParseRole(" Admin "); role != RoleAdmin {
+		t.Errorf("Wrong role %q", role)
+	}
+
+	if routes := RoleReader.Routes(nil); len(routes) != 1 || routes[0].Method != "GET" {
+		t.Errorf("Reader is allowed to %v", routes)
+	}
+	if routes := RoleUploader.Routes(nil); len(routes) != 2 || routes[1] != (Route{"PUT", "api/v1/repository/*"}) {
+		t.Errorf("Uploader is allowed to %v", routes)
+	}
+
+	if len(RoleAdmin.DeniedRoutes()) != 0 || len(RoleUploader.DeniedRoutes()) == 0 {
+		t.Error("Admin routes are not denied for non admins")
+	}
+
+	t.Setenv(RoleEnvName("ci"), "uploader")
+	if role := UserRole("ci"); role != RoleUploader {
+		t.Errorf("Wrong role %q", role)
+	}
+	if role := UserRole("unknown"); role != RoleReader {
+		t.Errorf("Wrong default role %q", role)
+	}
+}
+
+func TestScopes(t *testing.T) {
+	scopes, err := ParseScopes("acme/*, tools/gradle-*,")
+	if err != nil || len(scopes) != 2 {
+		t.Fatalf("Wrong scopes %v %v", scopes, err)
+	}
+	for _, malformed := range []string{"acme", "acme/app/main", "acme/app:main:x", "acme/app:", "/app"} {
+		if _, err := ParseScopes(malformed); err == nil {
+			t.Errorf("Malformed scope %q accepted", malformed)
+		}
+	}
+
+	cases := map[string]bool{
+		"acme/app":          true,
+		"tools/gradle-init": true,
+		"tools/maven":       false,
+		"other/app":         false,
+	}
+	for repo, expected := range cases {
+		if actual := ScopesAllow(scopes, repo, "main"); actual != expected {
+			t.Errorf("ScopesAllow(%s) = %v", repo, actual)
+		}
+	}
+	if !ScopesAllow(nil, "any/repo", "main") {
+		t.Error("Unscoped credentials are restricted")
+	}
+
+	if !UploadAllowed("uploader", "", "any/repo", "main") || !UploadAllowed("admin", "acme/*", "acme/app", "main") {
+		t.Error("Upload of uploader is denied")
+	}
+	if UploadAllowed("reader", "", "any/repo", "main") || UploadAllowed("", "", "any/repo", "main") || UploadAllowed("uploader", "acme/*", "other/app", "main") {
+		t.Error("Upload without role or out of scope is allowed")
+	}
+
+	if !ScopesInclude(scopes, []string{"acme/app", "tools/gradle-*"}) || ScopesInclude(scopes, []string{"tools/*"}) || ScopesInclude(scopes, nil) {
+		t.Error("Wrong scopes inclusion")
+	}
+
+	refScopes, err := ParseScopes("acme/app:release/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ScopesAllow(refScopes, "acme/app", "release/1.0") || ScopesAllow(refScopes, "acme/app", "main") {
+		t.Error("Ref scope is not applied")
+	}
+	if !ScopesInclude(refScopes, []string{"acme/app:release/2.0"}) || ScopesInclude(refScopes, []string{"acme/app"}) {
+		t.Error("Wrong ref scopes inclusion")
+	}
+	if routes := RoleUploader.Routes(refScopes); routes[1].Path != "api/v1/repository/acme/app/release/*" {
+		t.Errorf("Wrong ref scoped route %v", routes[1])
+	}
+
+	routes := RoleUploader.Routes(scopes)
+	if len(routes) != 3 || routes[1].Path != "api/v1/repository/acme/*/*" || routes[2].Path != "api/v1/repository/tools/gradle-*/*" {
+		t.Errorf("Wrong scoped routes %v", routes)
+	}
+}
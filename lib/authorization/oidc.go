@@ -0,0 +1,342 @@
+package authorization
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OIDCJwksCacheTtl = 10 * time.Minute
+	// OIDCJwksReloadInterval limits reloads forced by unknown kid, any caller could send token with made up kid
+	OIDCJwksReloadInterval = time.Minute
+	OIDCLeeway       = time.Minute
+)
+
+type OIDCConfig struct {
+	Issuer   string
+	Audience string
+	// JwksUrl is https:// url or local file path, file:// prefix is optional
+	JwksUrl string
+	// AllowedRepositories are org/repo globs or owners (GitHub org, GitLab group). Issuers like GitHub Actions
+	// are shared by every repository and audience is chosen by the workflow, so tokens are accepted only from these
+	AllowedRepositories []string
+}
+
+// OIDCConfigFromEnv reads OIDC_ISSUER, OIDC_AUDIENCE, OIDC_JWKS_URL and comma separated OIDC_ALLOWED_REPOSITORIES,
+// returns nil when OIDC is not configured and error when it is configured without allowed repositories
+func OIDCConfigFromEnv() (*OIDCConfig, error) {
+	cfg := OIDCConfig{
+		Issuer:   os.Getenv("OIDC_ISSUER"),
+		Audience: os.Getenv("OIDC_AUDIENCE"),
+		JwksUrl:  os.Getenv("OIDC_JWKS_URL"),
+	}
+	if cfg.Issuer == "" || cfg.Audience == "" || cfg.JwksUrl == "" {
+		return nil, nil
+	}
+	for _, pattern := range strings.Split(os.Getenv("OIDC_ALLOWED_REPOSITORIES"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("malformed OIDC_ALLOWED_REPOSITORIES pattern %q", pattern)
+		}
+		cfg.AllowedRepositories = append(cfg.AllowedRepositories, pattern)
+	}
+	if len(cfg.AllowedRepositories) == 0 {
+		return nil, fmt.Errorf("OIDC_ALLOWED_REPOSITORIES is required, OIDC is disabled")
+	}
+	return &cfg, nil
+}
+
+type OIDC struct {
+	config OIDCConfig
+	http   *http.Client
+
+	mu         sync.Mutex
+	keys       map[string]*rsa.PublicKey
+	loadedAt   time.Time
+	reloadedAt time.Time
+	// loading is closed when reload in progress is done, concurrent callers wait for it instead of fetching again
+	loading chan struct{}
+	loadErr error
+}
+
+type OIDCClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	// GitHub Actions
+	Repository      string `json:"repository"`
+	RepositoryOwner string `json:"repository_owner"`
+	Ref             string `json:"ref"`
+	// GitLab CI
+	ProjectPath   string `json:"project_path"`
+	NamespacePath string `json:"namespace_path"`
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = []string{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+type jwks struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func NewOIDC(config OIDCConfig) *OIDC {
+	return &OIDC{
+		config: config,
+		http:   &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// IsJwt distinguishes JWT from API tokens in Bearer header
+func IsJwt(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Verify checks RS256 signature against JWKS, issuer, audience and expiry
+func (o *OIDC) Verify(token string, now time.Time) (*OIDCClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed jwt")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJwtPart(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", header.Alg)
+	}
+
+	key, err := o.key(header.Kid, now)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed jwt signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("invalid jwt signature")
+	}
+
+	var claims OIDCClaims
+	if err := decodeJwtPart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if claims.Issuer != o.config.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.hasAudience(o.config.Audience) {
+		return nil, fmt.Errorf("unexpected audience %v", claims.Audience)
+	}
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(OIDCLeeway)) {
+		return nil, fmt.Errorf("jwt expired")
+	}
+	if claims.NotBefore != 0 && now.Add(OIDCLeeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, fmt.Errorf("jwt is not valid yet")
+	}
+	if claims.RepositoryName() == "" || claims.RefName() == "" {
+		return nil, fmt.Errorf("jwt has no repository or ref claims")
+	}
+	if !o.allowed(claims) {
+		return nil, fmt.Errorf("repository %q is not allowed", claims.RepositoryName())
+	}
+	// Upload route and scopes take org/repo, projects of GitLab subgroups (group/subgroup/project) have no place there
+	if strings.Count(claims.RepositoryName(), "/") != 1 {
+		return nil, fmt.Errorf("repository %q is not org/repo, subgroup projects are not supported", claims.RepositoryName())
+	}
+	// Merge refs of pull requests are not branches, their dependencies are not stored
+	if strings.HasPrefix(claims.Ref, "refs/pull/") || strings.HasPrefix(claims.Ref, "refs/merge-requests/") {
+		return nil, fmt.Errorf("pull request ref %q is not uploaded", claims.Ref)
+	}
+
+	return &claims, nil
+}
+
+// allowed matches repository and its owner (GitHub org, GitLab group namespace) against AllowedRepositories
+func (o *OIDC) allowed(claims OIDCClaims) bool {
+	owner := claims.RepositoryOwner
+	if owner == "" {
+		owner = claims.NamespacePath
+	}
+	for _, pattern := range o.config.AllowedRepositories {
+		if matched, _ := path.Match(pattern, claims.RepositoryName()); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, owner); owner != "" && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *OIDCClaims) hasAudience(expected string) bool {
+	for _, aud := range c.Audience {
+		if aud == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *OIDCClaims) RepositoryName() string {
+	if c.Repository != "" {
+		return c.Repository
+	}
+	return c.ProjectPath
+}
+
+// RefName strips refs/heads/ and refs/tags/ prefixes the same way as GITHUB_REF_NAME does, Verify rejects pull request refs
+func (c *OIDCClaims) RefName() string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+		if strings.HasPrefix(c.Ref, prefix) {
+			return strings.TrimPrefix(c.Ref, prefix)
+		}
+	}
+	return c.Ref
+}
+
+// Scope limits uploads of pipeline to its own repository and ref
+func (c *OIDCClaims) Scope() string {
+	return fmt.Sprintf("%s:%s", c.RepositoryName(), c.RefName())
+}
+
+func (o *OIDC) key(kid string, now time.Time) (*rsa.PublicKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// Unknown kid forces reload to pick up rotated keys, but not more often than OIDCJwksReloadInterval
+	key, found := o.keys[kid]
+	if found && now.Sub(o.loadedAt) < OIDCJwksCacheTtl {
+		return key, nil
+	}
+	if now.Sub(o.reloadedAt) < OIDCJwksReloadInterval && o.loading == nil {
+		if found {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown jwt key %q", kid)
+	}
+
+	if loading := o.loading; loading != nil {
+		o.mu.Unlock()
+		<-loading
+		o.mu.Lock()
+	} else {
+		loading = make(chan struct{})
+		o.loading = loading
+		o.reloadedAt = now
+
+		// Fetch is done without lock, so verification of known keys is not blocked by slow JWKS endpoint
+		o.mu.Unlock()
+		keys, err := o.loadKeys()
+		o.mu.Lock()
+
+		if err == nil {
+			o.keys = keys
+			o.loadedAt = now
+		}
+		o.loadErr = err
+		o.loading = nil
+		close(loading)
+	}
+
+	if key, found := o.keys[kid]; found {
+		return key, nil
+	}
+	if o.loadErr != nil {
+		return nil, o.loadErr
+	}
+	return nil, fmt.Errorf("unknown jwt key %q", kid)
+}
+
+func (o *OIDC) loadKeys() (map[string]*rsa.PublicKey, error) {
+	var reader io.ReadCloser
+	if strings.HasPrefix(o.config.JwksUrl, "https://") || strings.HasPrefix(o.config.JwksUrl, "http://") {
+		resp, err := o.http.Get(o.config.JwksUrl)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("jwks request failed: %s", resp.Status)
+		}
+		reader = resp.Body
+	} else {
+		file, err := os.Open(strings.TrimPrefix(o.config.JwksUrl, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		reader = file
+	}
+	defer reader.Close()
+
+	var set jwks
+	if err := json.NewDecoder(reader).Decode(&set); err != nil {
+		return nil, fmt.Errorf("malformed jwks: %s", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			return nil, fmt.Errorf("malformed jwks key %q", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func decodeJwtPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("malformed jwt")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("malformed jwt")
+	}
+	return nil
+}
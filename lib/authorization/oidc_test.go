@@ -0,0 +1,168 @@
+package authorization
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func signJwt(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	jwksData, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": "k1",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	if err := os.WriteFile(jwksFile, jwksData, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	oidc := NewOIDC(OIDCConfig{
+		Issuer:              "https://token.actions.githubusercontent.com",
+		Audience:            "gradle-dependencies",
+		JwksUrl:             "file://" + jwksFile,
+		AllowedRepositories: []string{"acme/*", "group"},
+	})
+	claims := func(override map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":              "https://token.actions.githubusercontent.com",
+			"aud":              "gradle-dependencies",
+			"sub":              "repo:acme/app:ref:refs/heads/main",
+			"exp":              now.Add(5 * time.Minute).Unix(),
+			"repository":       "acme/app",
+			"repository_owner": "acme",
+			"ref":              "refs/heads/main",
+		}
+		for k, v := range override {
+			c[k] = v
+		}
+		return c
+	}
+
+	verified, err := oidc.Verify(signJwt(t, key, "k1", claims(nil)), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Scope() != "acme/app:main" {
+		t.Errorf("Wrong scope %s", verified.Scope())
+	}
+
+	verified, err = oidc.Verify(signJwt(t, key, "k1", claims(map[string]interface{}{
+		"aud":              []string{"other", "gradle-dependencies"},
+		"repository":       "",
+		"repository_owner": "",
+		"project_path":     "group/project",
+		"namespace_path":   "group",
+		"ref":              "feature/x",
+	})), now)
+	if err != nil || verified.Scope() != "group/project:feature/x" {
+		t.Errorf("GitLab claims are not mapped: %v %v", verified, err)
+	}
+
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	invalid := map[string]string{
+		"issuer":    signJwt(t, key, "k1", claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+		"audience":  signJwt(t, key, "k1", claims(map[string]interface{}{"aud": "other"})),
+		"expired":   signJwt(t, key, "k1", claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+		"signature": signJwt(t, otherKey, "k1", claims(nil)),
+		"kid":       signJwt(t, key, "k2", claims(nil)),
+		"malformed": "a.b.c",
+		// Valid token of any other GitHub repository is signed by the same issuer
+		"repository": signJwt(t, key, "k1", claims(map[string]interface{}{"repository": "evil/app", "repository_owner": "evil"})),
+		"subgroup": signJwt(t, key, "k1", claims(map[string]interface{}{
+			"repository": "", "repository_owner": "", "project_path": "group/sub/project", "namespace_path": "group/sub",
+		})),
+		"pull request": signJwt(t, key, "k1", claims(map[string]interface{}{"ref": "refs/pull/1/merge"})),
+	}
+	for name, token := range invalid {
+		if _, err := oidc.Verify(token, now); err == nil {
+			t.Errorf("Token with wrong %s accepted", name)
+		}
+	}
+}
+
+func TestOIDCKeyReload(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	oidc := NewOIDC(OIDCConfig{JwksUrl: server.URL})
+	if _, err := oidc.key("k1", now); err != nil || fetches != 1 {
+		t.Fatalf("Key is not loaded: %v, %d fetches", err, fetches)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := oidc.key("unknown", now.Add(time.Duration(i)*time.Second)); err == nil {
+			t.Error("Unknown kid accepted")
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Unknown kids forced %d fetches", fetches)
+	}
+	if _, err := oidc.key("unknown", now.Add(OIDCJwksReloadInterval)); err == nil || fetches != 2 {
+		t.Errorf("Keys are not reloaded after interval: %v, %d fetches", err, fetches)
+	}
+	if _, err := oidc.key("k1", now.Add(OIDCJwksReloadInterval+time.Second)); err != nil || fetches != 2 {
+		t.Errorf("Cached key is not used: %v, %d fetches", err, fetches)
+	}
+}
+
+func TestOIDCConfigFromEnv(t *testing.T) {
+	t.Setenv("OIDC_ISSUER", "https://token.actions.githubusercontent.com")
+	t.Setenv("OIDC_AUDIENCE", "gradle-dependencies")
+	t.Setenv("OIDC_JWKS_URL", "https://token.actions.githubusercontent.com/.well-known/jwks")
+
+	if cfg, err := OIDCConfigFromEnv(); err == nil || cfg != nil {
+		t.Errorf("OIDC is enabled without allowed repositories: %+v", cfg)
+	}
+
+	t.Setenv("OIDC_ALLOWED_REPOSITORIES", "acme, tools/gradle-*")
+	cfg, err := OIDCConfigFromEnv()
+	if err != nil || len(cfg.AllowedRepositories) != 2 || cfg.AllowedRepositories[0] != "acme" {
+		t.Errorf("Wrong config %+v %v", cfg, err)
+	}
+}
@@ -0,0 +1,92 @@
+package authorization
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Role string
+
+const (
+	RoleReader   Role = "reader"
+	RoleUploader Role = "uploader"
+	RoleAdmin    Role = "admin"
+)
+
+// Route is a method and path pattern as used in execute-api resource ARN
+type Route struct {
+	Method string
+	Path   string
+}
+
+var roleRank = map[Role]int{
+	RoleReader:   1,
+	RoleUploader: 2,
+	RoleAdmin:    3,
+}
+
+func ParseRole(role string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(role)))
+	if _, found := roleRank[r]; !found {
+		return "", fmt.Errorf("unknown role %q, expected reader, uploader or admin", role)
+	}
+	return r, nil
+}
+
+// Includes reports whether role grants at least everything other role does
+func (r Role) Includes(other Role) bool {
+	return roleRank[r] >= roleRank[other] && roleRank[other] > 0
+}
+
+// Routes returns routes allowed for role, uploads are limited to org/repo scopes when given
+func (r Role) Routes(scopes []string) []Route {
+	routes := []Route{
+		{Method: "GET", Path: "*"},
+	}
+	if r.Includes(RoleUploader) {
+		routes = append(routes, uploadRoutes(scopes)...)
+	}
+	if r.Includes(RoleAdmin) {
+		routes = append(routes, Route{Method: "*", Path: "api/v1/*"})
+	}
+	return routes
+}
+
+// DeniedRoutes returns admin only routes for other roles, they are otherwise covered by GET *
+func (r Role) DeniedRoutes() []Route {
+	if r.Includes(RoleAdmin) {
+		return nil
+	}
+	return AdminRoutes()
+}
+
+// AdminRoutes are read routes available only for admins
+func AdminRoutes() []Route {
+	return []Route{
+		{Method: "*", Path: "api/v1/audit"},
+		{Method: "*", Path: "api/v1/webhooks"},
+		{Method: "*", Path: "api/v1/webhooks/*"},
+	}
+}
+
+// TokensRoutes are routes to manage own tokens, allowed for users but never for tokens
+func TokensRoutes() []Route {
+	return []Route{
+		{Method: "*", Path: "api/v1/tokens"},
+		{Method: "*", Path: "api/v1/tokens/*"},
+	}
+}
+
+// RoleEnvName returns env var name holding role of user
+func RoleEnvName(username string) string {
+	return strings.Replace(CredentialsEnvName(username), "USER_", "ROLE_", 1)
+}
+
+// UserRole returns configured role of user, reader when not configured
+func UserRole(username string) Role {
+	if role, err := ParseRole(os.Getenv(RoleEnvName(username))); err == nil {
+		return role
+	}
+	return RoleReader
+}
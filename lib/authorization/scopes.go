@@ -0,0 +1,123 @@
+package authorization
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var scopeRegexp = regexp.MustCompile(`^[A-Za-z0-9._*-]+/[A-Za-z0-9._*-]+(:[^:,\s]+)?$`)
+
+// ParseScopes parses comma separated org/repo[:ref] glob scopes, e.g. "acme/*,tools/gradle-*:main"
+func ParseScopes(value string) ([]string, error) {
+	var scopes []string
+	for _, scope := range strings.Split(value, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !scopeRegexp.MatchString(scope) {
+			return nil, fmt.Errorf("malformed scope %q, expected org/repo[:ref] glob", scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// ScopesAllow reports whether repo and ref match any scope, no scopes means no restriction
+func ScopesAllow(scopes []string, repo string, ref string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if scopeAllows(scope, repo, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadAllowed double-checks authorizer context of upload, so misconfigured policy could not be bypassed.
+// Missing or lower role denies upload, scopes without restriction are trusted only for uploaders and admins
+func UploadAllowed(role string, scopes string, repo string, ref string) bool {
+	parsed, err := ParseRole(role)
+	if err != nil || !parsed.Includes(RoleUploader) {
+		return false
+	}
+	parsedScopes, err := ParseScopes(scopes)
+	return err == nil && ScopesAllow(parsedScopes, repo, ref)
+}
+
+// ScopesInclude reports whether every scope of other is covered by scopes, so token could not be wider than its owner
+func ScopesInclude(scopes []string, other []string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	if len(other) == 0 {
+		return false
+	}
+	for _, scope := range other {
+		repo, ref := splitScope(scope)
+		included := false
+		for _, ownerScope := range scopes {
+			// scope without ref allows any ref, so it is covered only by owner scope without ref
+			if _, ownerRef := splitScope(ownerScope); ref == "" && ownerRef != "" {
+				continue
+			}
+			if scopeAllows(ownerScope, repo, ref) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
+
+func scopeAllows(scope string, repo string, ref string) bool {
+	repoPattern, refPattern := splitScope(scope)
+	if matched, _ := path.Match(repoPattern, repo); !matched {
+		return false
+	}
+	if refPattern == "" {
+		return true
+	}
+	matched, _ := path.Match(refPattern, ref)
+	return matched
+}
+
+func splitScope(scope string) (repo string, ref string) {
+	if i := strings.Index(scope, ":"); i >= 0 {
+		return scope[:i], scope[i+1:]
+	}
+	return scope, ""
+}
+
+// ScopesEnvName returns env var name holding upload scopes of user
+func ScopesEnvName(username string) string {
+	return strings.Replace(CredentialsEnvName(username), "USER_", "SCOPES_", 1)
+}
+
+// UserScopes returns configured upload scopes of user, malformed scopes deny uploads at all
+func UserScopes(username string) ([]string, error) {
+	return ParseScopes(os.Getenv(ScopesEnvName(username)))
+}
+
+func uploadRoutes(scopes []string) []Route {
+	if len(scopes) == 0 {
+		return []Route{{Method: "PUT", Path: "api/v1/repository/*"}}
+	}
+	routes := make([]Route, 0, len(scopes))
+	for _, scope := range scopes {
+		repo, ref := splitScope(scope)
+		if ref == "" {
+			ref = "*"
+		}
+		routes = append(routes, Route{Method: "PUT", Path: fmt.Sprintf("api/v1/repository/%s/%s", repo, ref)})
+	}
+	return routes
+}
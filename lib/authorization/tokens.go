@@ -0,0 +1,123 @@
+package authorization
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"strings"
+	"time"
+)
+
+const (
+	TokenPrefix = "gdg"
+
+	TokenDefaultExpiration = 90 * 24 * time.Hour
+	TokenMaxExpiration     = 365 * 24 * time.Hour
+)
+
+// TokenStore is the part of storage used to look up tokens
+type TokenStore interface {
+	GetToken(ctxId string, id string) (*storage.TokenDto, *storage.StorageErrorRest)
+}
+
+// GenerateToken returns new token in form gdg_<id>_<secret> together with its public id and secret
+func GenerateToken() (token string, id string, secret string, err error) {
+	idBytes := make([]byte, 8)
+	secretBytes := make([]byte, 24)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+	id = hex.EncodeToString(idBytes)
+	secret = hex.EncodeToString(secretBytes)
+	return fmt.Sprintf("%s_%s_%s", TokenPrefix, id, secret), id, secret, nil
+}
+
+// ParseToken splits token into public id and secret
+func ParseToken(token string) (id string, secret string, ok bool) {
+	parts := strings.Split(token, "_")
+	if len(parts) != 3 || parts[0] != TokenPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func HashTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// TokenExpiration validates requested lifetime in days, 0 means default
+func TokenExpiration(days int) (time.Duration, error) {
+	if days < 0 {
+		return 0, fmt.Errorf("token expiration must be positive")
+	}
+	if days == 0 {
+		return TokenDefaultExpiration, nil
+	}
+	expiration := time.Duration(days) * 24 * time.Hour
+	if expiration > TokenMaxExpiration {
+		return 0, fmt.Errorf("token expiration must not exceed %d days", int(TokenMaxExpiration.Hours()/24))
+	}
+	return expiration, nil
+}
+
+// CheckToken returns stored token when it matches, is not revoked and not expired
+func (svc *Authorization) CheckToken(reqId string, token string, now time.Time) (*storage.TokenDto, bool) {
+	id, secret, ok := ParseToken(token)
+	if !ok || svc.tokens == nil {
+		svc.logger.Debug("Malformed token",
+			zap.String("reqId", reqId),
+		)
+		return nil, false
+	}
+
+	stored, err := svc.tokens.GetToken(reqId, id)
+	if err != nil {
+		svc.logger.Debug("Token not found",
+			zap.String("reqId", reqId),
+			zap.String("id", id),
+		)
+		return nil, false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored.Hash), []byte(HashTokenSecret(secret))) != 1 {
+		svc.logger.Warn("Token secret mismatch",
+			zap.String("reqId", reqId),
+			zap.String("id", id),
+		)
+		return nil, false
+	}
+	if stored.Revoked != "" {
+		svc.logger.Debug("Token revoked",
+			zap.String("reqId", reqId),
+			zap.String("id", id),
+		)
+		return nil, false
+	}
+	expires, errParse := time.Parse(time.RFC3339, stored.Expires)
+	if errParse != nil || !now.Before(expires) {
+		svc.logger.Debug("Token expired",
+			zap.String("reqId", reqId),
+			zap.String("id", id),
+		)
+		return nil, false
+	}
+
+	return stored, true
+}
+
+// TokenRole returns role granted to token, tokens created before roles were introduced are read only,
+// their owners could have lost upload access since then
+func TokenRole(token *storage.TokenDto) Role {
+	if role, err := ParseRole(token.Role); err == nil {
+		return role
+	}
+	return RoleReader
+}
@@ -0,0 +1,86 @@
+package authorization
+
+import (
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"testing"
+	"time"
+)
+
+type memoryTokens map[string]storage.TokenDto
+
+func (m memoryTokens) GetToken(ctxId string, id string) (*storage.TokenDto, *storage.StorageErrorRest) {
+	if token, found := m[id]; found {
+		return &token, nil
+	}
+	return nil, &storage.StorageErrorRest{Code: storage.ErrObjectNotFound, Id: id}
+}
+
+func TestParseToken(t *testing.T) {
+	token, id, secret, err := GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedId, parsedSecret, ok := ParseToken(token)
+	if !ok || parsedId != id || parsedSecret != secret {
+		t.Errorf("Token %s parsed as %s/%s", token, parsedId, parsedSecret)
+	}
+
+	for _, malformed := range []string{"", "gdg_abc", "xyz_abc_def", "gdg__def", "gdg_a_b_c"} {
+		if _, _, ok := ParseToken(malformed); ok {
+			t.Errorf("Malformed token %q accepted", malformed)
+		}
+	}
+}
+
+func TestTokenExpiration(t *testing.T) {
+	if d, err := TokenExpiration(0); err != nil || d != TokenDefaultExpiration {
+		t.Errorf("Wrong default expiration %v %v", d, err)
+	}
+	if d, err := TokenExpiration(7); err != nil || d != 7*24*time.Hour {
+		t.Errorf("Wrong expiration %v %v", d, err)
+	}
+	for _, days := range []int{-1, 366} {
+		if _, err := TokenExpiration(days); err == nil {
+			t.Errorf("Expiration of %d days accepted", days)
+		}
+	}
+}
+
+func TestCheckToken(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	token, id, secret, _ := GenerateToken()
+	revokedToken, revokedId, revokedSecret, _ := GenerateToken()
+	expiredToken, expiredId, expiredSecret, _ := GenerateToken()
+	store := memoryTokens{
+		id:        {Id: id, Hash: HashTokenSecret(secret), Owner: "ci", Expires: "2021-07-01T00:00:00Z"},
+		revokedId: {Id: revokedId, Hash: HashTokenSecret(revokedSecret), Owner: "ci", Expires: "2021-07-01T00:00:00Z", Revoked: "2021-05-01T00:00:00Z"},
+		expiredId: {Id: expiredId, Hash: HashTokenSecret(expiredSecret), Owner: "ci", Expires: "2021-05-01T00:00:00Z"},
+	}
+	svc, _ := NewAuthorization(zap.NewNop(), store)
+
+	if stored, ok := svc.CheckToken("test", token, now); !ok || stored.Owner != "ci" {
+		t.Errorf("Valid token rejected")
+	}
+	if _, ok := svc.CheckToken("test", "gdg_"+id+"_wrong", now); ok {
+		t.Errorf("Wrong secret accepted")
+	}
+	if _, ok := svc.CheckToken("test", revokedToken, now); ok {
+		t.Errorf("Revoked token accepted")
+	}
+	if _, ok := svc.CheckToken("test", expiredToken, now); ok {
+		t.Errorf("Expired token accepted")
+	}
+	if _, ok := svc.CheckToken("test", "gdg_unknown_secret", now); ok {
+		t.Errorf("Unknown token accepted")
+	}
+}
+
+func TestTokenRole(t *testing.T) {
+	if role := TokenRole(&storage.TokenDto{Role: "uploader"}); role != RoleUploader {
+		t.Errorf("Wrong token role %q", role)
+	}
+	if role := TokenRole(&storage.TokenDto{}); role != RoleReader {
+		t.Errorf("Token without role is %q", role)
+	}
+}
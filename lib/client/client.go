@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"gradle-serverless-dependencies-graph/lib/conflicts"
+	"gradle-serverless-dependencies-graph/lib/freshness"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/policy"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to deployed API using the same routes as web pages, asking for JSON responses
+type Client struct {
+	BaseUrl  string
+	User     string
+	Password string
+	Token    string
+	Http     *http.Client
+}
+
+type UploadResult struct {
+	Status       string                 `json:"status"`
+	UsedCapacity float64                `json:"used-capacity"`
+	Violations   []storage.ViolationDto `json:"violations,omitempty"`
+}
+
+func NewClient(baseUrl string, user string, password string) (*Client, error) {
+	if baseUrl == "" {
+		return nil, fmt.Errorf("api url is not configured")
+	}
+	if _, err := url.Parse(baseUrl); err != nil {
+		return nil, errors.Wrap(err, "malformed api url")
+	}
+
+	return &Client{
+		BaseUrl:  strings.TrimRight(baseUrl, "/"),
+		User:     user,
+		Password: password,
+		Http:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (c *Client) Upload(repo string, ref string, deps storage.DependenciesRest) (*UploadResult, error) {
+	var result UploadResult
+	err := c.do(http.MethodPut, fmt.Sprintf("/api/v1/repository/%s/%s", repo, ref), nil, deps, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetPolicy replaces stored policy, admin role is required
+func (c *Client) SetPolicy(p *policy.Policy) (*storage.PolicyDto, error) {
+	var result storage.PolicyDto
+	if err := c.do(http.MethodPut, "/api/v1/policy", nil, p, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Webhooks lists webhooks, admin only
+func (c *Client) Webhooks() ([]storage.WebhookDto, error) {
+	var result []storage.WebhookDto
+	err := c.do(http.MethodGet, "/api/v1/webhooks", nil, nil, &result)
+	return result, err
+}
+
+// CreateWebhook subscribes url to dependency changes, returned secret verifies signatures and is never shown again
+func (c *Client) CreateWebhook(hook storage.WebhookDto) (*storage.WebhookDto, string, error) {
+	var result struct {
+		storage.WebhookDto
+		Secret string `json:"secret"`
+	}
+	if err := c.do(http.MethodPost, "/api/v1/webhooks", nil, hook, &result); err != nil {
+		return nil, "", err
+	}
+	return &result.WebhookDto, result.Secret, nil
+}
+
+func (c *Client) DeleteWebhook(id string) error {
+	return c.do(http.MethodDelete, "/api/v1/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Violations lists policy violations of repo/ref, or all of them when repo is empty
+func (c *Client) Violations(repo string, ref string) ([]storage.ViolationDto, error) {
+	path := "/violation"
+	if repo != "" {
+		path = fmt.Sprintf("/violation/%s/%s", repo, ref)
+	}
+
+	var result []storage.ViolationDto
+	err := c.do(http.MethodGet, path, nil, nil, &result)
+	return result, err
+}
+
+// Licenses groups dependencies of repo/ref by license, or all stored dependencies matching license id or family
+func (c *Client) Licenses(repo string, ref string, license string) ([]licenses.Usage, error) {
+	path := "/license"
+	switch {
+	case repo != "":
+		path = fmt.Sprintf("/license/%s/%s", repo, ref)
+	case license != "":
+		path = "/license/" + url.PathEscape(license)
+	}
+
+	var result []licenses.Usage
+	err := c.do(http.MethodGet, path, nil, nil, &result)
+	return result, err
+}
+
+// Conflicts returns dependency conflicts of repo/ref or of all stored repo/refs, rule limits them to single rule id
+func (c *Client) Conflicts(repo string, ref string, rule string) ([]conflicts.Conflict, error) {
+	path := "/conflict"
+	if repo != "" {
+		path = fmt.Sprintf("/conflict/%s/%s", repo, ref)
+	}
+	query := url.Values{}
+	if rule != "" {
+		query.Set("rule", rule)
+	}
+
+	var result []conflicts.Conflict
+	err := c.do(http.MethodGet, path, query, nil, &result)
+	return result, err
+}
+
+// Freshness returns freshness dashboard, org limits repo/refs and trend to single org
+func (c *Client) Freshness(org string) (*freshness.Dashboard, error) {
+	query := url.Values{}
+	if org != "" {
+		query.Set("org", org)
+	}
+
+	var result freshness.Dashboard
+	if err := c.do(http.MethodGet, "/freshness", query, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FreshnessHistory returns daily freshness snapshots of repo/ref
+func (c *Client) FreshnessHistory(repo string, ref string) ([]storage.FreshnessDto, error) {
+	var result []storage.FreshnessDto
+	err := c.do(http.MethodGet, fmt.Sprintf("/freshness/%s/%s", repo, ref), nil, nil, &result)
+	return result, err
+}
+
+// RepoGraph returns links between repositories through internal artifacts
+func (c *Client) RepoGraph() ([]graph.RepoEdge, error) {
+	var result []graph.RepoEdge
+	err := c.do(http.MethodGet, "/repository-graph", nil, nil, &result)
+	return result, err
+}
+
+// RepoLinks returns upstream and downstream repositories of repo
+func (c *Client) RepoLinks(repo string) (*graph.RepoLinks, error) {
+	var result graph.RepoLinks
+	if err := c.do(http.MethodGet, "/repository-graph/"+repo, nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Sarif returns SARIF log of repo/ref as is, file is location of dependencies declared in unknown file
+func (c *Client) Sarif(repo string, ref string, file string) (json.RawMessage, error) {
+	query := url.Values{}
+	if file != "" {
+		query.Set("file", file)
+	}
+
+	var result json.RawMessage
+	err := c.do(http.MethodGet, fmt.Sprintf("/api/v1/sarif/%s/%s", repo, ref), query, nil, &result)
+	return result, err
+}
+
+func (c *Client) Dependencies(repo string, ref string, filter *storage.StorageFilter) ([]storage.StorageDto, error) {
+	var result []storage.StorageDto
+	err := c.do(http.MethodGet, fmt.Sprintf("/repository/%s/%s", repo, ref), filterQuery(filter), nil, &result)
+	return result, err
+}
+
+func (c *Client) WhoUses(dependency string, version *string, filter *storage.StorageFilter) ([]storage.StorageDto, error) {
+	path := "/dependency/" + strings.Replace(dependency, ":", "/", 1)
+	if version != nil {
+		path = path + "/" + *version
+	}
+
+	var result []storage.StorageDto
+	err := c.do(http.MethodGet, path, filterQuery(filter), nil, &result)
+	return result, err
+}
+
+func (c *Client) DependencyNames(group string) ([]string, error) {
+	var items []storage.DependencyDto
+	if err := c.do(http.MethodGet, "/dependency/"+group, nil, nil, &items); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.Child)
+	}
+	return result, nil
+}
+
+func (c *Client) do(method string, path string, query url.Values, body interface{}, result interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("format", "json")
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.BaseUrl+path+"?"+query.Encode(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	} else if c.User != "" {
+		req.SetBasicAuth(c.User, c.Password)
+	}
+
+	resp, err := c.Http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s %s", method, path, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return errors.Wrapf(err, "%s %s: unexpected response", method, path)
+		}
+	}
+	return nil
+}
+
+func filterQuery(filter *storage.StorageFilter) url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+	if filter.Variant != "" {
+		query.Set("variant", filter.Variant)
+	}
+	if filter.BuildType != "" {
+		query.Set("build-type", filter.BuildType)
+	}
+	if filter.Flavor != "" {
+		query.Set("flavor", filter.Flavor)
+	}
+	return query
+}
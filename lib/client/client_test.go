@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNetrcCredentials(t *testing.T) {
+	netrc := `
+machine other.example.com login other password nope
+machine deps.example.com
+  login ci
+  password s3cret
+default login anonymous password none
+`
+	login, password, found := NetrcCredentials(strings.NewReader(netrc), "deps.example.com")
+	if !found || login != "ci" || password != "s3cret" {
+		t.Errorf("Wrong credentials %s/%s found=%v", login, password, found)
+	}
+
+	login, _, found = NetrcCredentials(strings.NewReader(netrc), "unknown.example.com")
+	if !found || login != "anonymous" {
+		t.Errorf("Default credentials are not used: %s", login)
+	}
+
+	if _, _, found = NetrcCredentials(strings.NewReader("machine a login b password c"), "x"); found {
+		t.Error("Credentials found for unknown host")
+	}
+}
+
+func TestClientWhoUses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "ci" || password != "s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/dependency/com.google.guava/guava/32.1.2-jre" || r.URL.Query().Get("format") != "json" || r.URL.Query().Get("variant") != "freeRelease" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode([]storage.StorageDto{
+			{Dependency: "com.google.guava:guava", Version: "32.1.2-jre", Repo: "acme/app", Ref: "main"},
+		})
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/", "ci", "s3cret")
+	version := "32.1.2-jre"
+	items, err := c.WhoUses("com.google.guava:guava", &version, &storage.StorageFilter{Variant: "freeRelease"})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	if len(items) != 1 || items[0].Repo != "acme/app" {
+		t.Errorf("Wrong response %+v", items)
+	}
+
+	c.Password = "wrong"
+	if _, err = c.WhoUses("com.google.guava:guava", &version, nil); err == nil {
+		t.Error("Error is nil")
+	}
+}
+
+func TestClientToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gdg_id_secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(UploadResult{Status: "ok"})
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, "ci", "s3cret")
+	c.Token = "gdg_id_secret"
+	result, err := c.Upload("acme/app", "main", storage.DependenciesRest{})
+	if err != nil || result.Status != "ok" {
+		t.Errorf("Upload with token failed: %+v %v", result, err)
+	}
+}
+
+func TestClientCreateWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var hook storage.WebhookDto
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/webhooks" || json.NewDecoder(r.Body).Decode(&hook) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1f","url":"` + hook.Url + `","repos":["acme/*"],"secret":"abc"}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, "ci", "s3cret")
+	hook, secret, err := c.CreateWebhook(storage.WebhookDto{Url: "https://ci.example.com/hook", Repos: []string{"acme/*"}})
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	if hook.Id != "1f" || hook.Url != "https://ci.example.com/hook" || secret != "abc" {
+		t.Errorf("Wrong response %+v, secret %s", hook, secret)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// NetrcCredentials looks up login and password for host in netrc formatted content:
+//
+//	machine deps.example.com login ci password secret
+func NetrcCredentials(reader io.Reader, host string) (string, string, bool) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanWords)
+
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+
+	var login, password string
+	matched, found := false, false
+	for idx := 0; idx < len(tokens); idx++ {
+		switch tokens[idx] {
+		case "machine", "default":
+			if found {
+				return login, password, true
+			}
+			if tokens[idx] == "default" {
+				matched = true
+			} else if idx+1 < len(tokens) {
+				idx++
+				matched = strings.EqualFold(tokens[idx], host)
+			}
+			found = matched
+			login, password = "", ""
+		case "login", "password", "account", "macdef":
+			if idx+1 >= len(tokens) {
+				break
+			}
+			idx++
+			if !matched {
+				continue
+			}
+			if tokens[idx-1] == "login" {
+				login = tokens[idx]
+			} else if tokens[idx-1] == "password" {
+				password = tokens[idx]
+			}
+		}
+	}
+
+	return login, password, found
+}
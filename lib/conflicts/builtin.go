@@ -0,0 +1,90 @@
+package conflicts
+
+// javaxJakarta pairs javax and jakarta artifacts of the same API. Jakarta artifacts before namespace change
+// (servlet 4, most others 1.x-2.x) repackage javax classes, later ones mix javax and jakarta namespaces
+var javaxJakarta = []struct {
+	id      string
+	javax   []string
+	jakarta []string
+}{
+	{"servlet", []string{"javax.servlet:javax.servlet-api", "javax.servlet:servlet-api"}, []string{"jakarta.servlet:jakarta.servlet-api"}},
+	{"annotation", []string{"javax.annotation:javax.annotation-api", "javax.annotation:jsr250-api"}, []string{"jakarta.annotation:jakarta.annotation-api"}},
+	{"inject", []string{"javax.inject:javax.inject"}, []string{"jakarta.inject:jakarta.inject-api"}},
+	{"jaxb", []string{"javax.xml.bind:jaxb-api"}, []string{"jakarta.xml.bind:jakarta.xml.bind-api"}},
+	{"validation", []string{"javax.validation:validation-api"}, []string{"jakarta.validation:jakarta.validation-api"}},
+	{"persistence", []string{"javax.persistence:javax.persistence-api"}, []string{"jakarta.persistence:jakarta.persistence-api"}},
+	{"ws-rs", []string{"javax.ws.rs:javax.ws.rs-api", "javax.ws.rs:jsr311-api"}, []string{"jakarta.ws.rs:jakarta.ws.rs-api"}},
+}
+
+// Builtin returns rules for well known conflicts, new slice every time so callers may change it
+func Builtin() []Rule {
+	rules := []Rule{
+		{
+			Id:          "slf4j-bindings",
+			Description: "Several SLF4J bindings, only one of them is used at runtime",
+			Families: []Family{
+				{Name: "logback", Artifacts: []string{"ch.qos.logback:logback-classic"}},
+				{Name: "log4j2", Artifacts: []string{"org.apache.logging.log4j:log4j-slf4j-impl", "org.apache.logging.log4j:log4j-slf4j2-impl"}},
+				{Name: "log4j12", Artifacts: []string{"org.slf4j:slf4j-log4j12", "org.slf4j:slf4j-reload4j"}},
+				{Name: "simple", Artifacts: []string{"org.slf4j:slf4j-simple"}},
+				{Name: "nop", Artifacts: []string{"org.slf4j:slf4j-nop"}},
+				{Name: "jdk14", Artifacts: []string{"org.slf4j:slf4j-jdk14"}},
+				{Name: "jcl", Artifacts: []string{"org.slf4j:slf4j-jcl"}},
+			},
+		},
+		{
+			Id:          "log4j-reload4j",
+			Description: "Several artifacts providing org.apache.log4j classes",
+			Families: []Family{
+				{Name: "log4j", Artifacts: []string{"log4j:log4j"}},
+				{Name: "reload4j", Artifacts: []string{"ch.qos.reload4j:reload4j"}},
+				{Name: "log4j-over-slf4j", Artifacts: []string{"org.slf4j:log4j-over-slf4j"}},
+			},
+		},
+		{
+			Id:          "log4j2-slf4j-loop",
+			Description: "Log4j 2 to SLF4J adapter together with SLF4J to Log4j 2 binding route log events in a loop",
+			Families: []Family{
+				{Name: "log4j-to-slf4j", Artifacts: []string{"org.apache.logging.log4j:log4j-to-slf4j"}},
+				{Name: "log4j-slf4j-impl", Artifacts: []string{"org.apache.logging.log4j:log4j-slf4j-impl", "org.apache.logging.log4j:log4j-slf4j2-impl"}},
+			},
+		},
+		{
+			Id:          "commons-logging",
+			Description: "Several artifacts providing org.apache.commons.logging classes",
+			Families: []Family{
+				{Name: "commons-logging", Artifacts: []string{"commons-logging:commons-logging"}},
+				{Name: "jcl-over-slf4j", Artifacts: []string{"org.slf4j:jcl-over-slf4j"}},
+				{Name: "spring-jcl", Artifacts: []string{"org.springframework:spring-jcl"}},
+			},
+		},
+		{
+			Id:          "guava-google-collections",
+			Description: "Google Collections is predecessor of Guava, both provide com.google.common classes",
+			Families: []Family{
+				{Name: "guava", Artifacts: []string{"com.google.guava:guava"}},
+				{Name: "google-collections", Artifacts: []string{"com.google.collections:google-collections"}},
+			},
+		},
+		{
+			Id:          "bouncycastle-jdk-variants",
+			Description: "Bouncy Castle artifacts built for different JDKs provide the same org.bouncycastle classes",
+			Families: []Family{
+				{Name: "jdk15on", Artifacts: []string{"org.bouncycastle:*-jdk15on"}},
+				{Name: "jdk15to18", Artifacts: []string{"org.bouncycastle:*-jdk15to18"}},
+				{Name: "jdk18on", Artifacts: []string{"org.bouncycastle:*-jdk18on"}},
+			},
+		},
+	}
+	for _, api := range javaxJakarta {
+		rules = append(rules, Rule{
+			Id:          "javax-jakarta-" + api.id,
+			Description: "javax and jakarta artifacts of the same API, classes are duplicated or namespaces are mixed",
+			Families: []Family{
+				{Name: "javax", Artifacts: api.javax},
+				{Name: "jakarta", Artifacts: api.jakarta},
+			},
+		})
+	}
+	return rules
+}
@@ -0,0 +1,226 @@
+package conflicts
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"path"
+	"sort"
+)
+
+// Rule describes artifact families providing the same classes or functionality, repo/ref resolving artifacts
+// of two or more families at once has conflict. User rule replaces built-in one with the same id, e.g.:
+//
+//	conflicts:
+//	  - id: json-libraries
+//	    description: Single JSON library is expected
+//	    families:
+//	      - name: gson
+//	        artifacts: [com.google.code.gson:gson]
+//	      - name: jackson
+//	        artifacts: [com.fasterxml.jackson.core:jackson-databind]
+//	  - id: guava-google-collections
+//	    disabled: true
+type Rule struct {
+	Id          string   `json:"id" yaml:"id"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Families    []Family `json:"families,omitempty" yaml:"families,omitempty"`
+}
+
+// Family is set of group:name globs, optionally limited to Maven version range
+type Family struct {
+	Name      string   `json:"name" yaml:"name"`
+	Artifacts []string `json:"artifacts" yaml:"artifacts"`
+	Versions  string   `json:"versions,omitempty" yaml:"versions,omitempty"`
+
+	versions *maven.Range
+}
+
+// Conflict is rule matched by repo/ref, Variants lists Android variants having conflict, empty for other projects
+type Conflict struct {
+	Repo        string   `json:"repo"`
+	Ref         string   `json:"ref"`
+	Rule        string   `json:"rule"`
+	Description string   `json:"description,omitempty"`
+	Variants    []string `json:"variants,omitempty"`
+	Families    []Match  `json:"families"`
+}
+
+// Match is family found in dependencies, Artifacts are group:name:version
+type Match struct {
+	Family    string   `json:"family"`
+	Artifacts []string `json:"artifacts"`
+}
+
+// Validate checks user rules, so malformed ones are rejected when policy is stored
+func Validate(rules []Rule) error {
+	ids := map[string]bool{}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Id == "" {
+			return fmt.Errorf("conflict rule #%d: id is required", i+1)
+		}
+		if ids[rule.Id] {
+			return fmt.Errorf("conflict rule %s: duplicate id", rule.Id)
+		}
+		ids[rule.Id] = true
+		if rule.Disabled {
+			continue
+		}
+		if err := rule.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rules merges built-in rules with user ones, user rule replaces built-in one with the same id or disables it
+func Rules(user []Rule) []Rule {
+	byId := map[string]Rule{}
+	var ids []string
+	for _, rule := range append(Builtin(), user...) {
+		if _, ok := byId[rule.Id]; !ok {
+			ids = append(ids, rule.Id)
+		}
+		byId[rule.Id] = rule
+	}
+
+	var result []Rule
+	for _, id := range ids {
+		if rule := byId[id]; !rule.Disabled {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// Detect finds conflicts in items of any number of repo/refs, every variant of Android project is checked separately.
+// Malformed rules are skipped, Validate reports them
+func Detect(rules []Rule, items []storage.StorageDto) []Conflict {
+	var valid []Rule
+	for _, rule := range rules {
+		if err := rule.compile(); err == nil {
+			valid = append(valid, rule)
+		}
+	}
+
+	type scope struct{ repo, ref, variant string }
+	scopes := map[scope][]storage.StorageDto{}
+	for _, item := range items {
+		if item.Kind != "" && item.Kind != storage.KindLibrary {
+			continue
+		}
+		key := scope{item.Repo, item.Ref, item.Variant}
+		scopes[key] = append(scopes[key], item)
+	}
+
+	conflicts := map[string]*Conflict{}
+	families := map[string]map[string]map[string]bool{}
+	for key, scoped := range scopes {
+		for _, rule := range valid {
+			matched := rule.match(scoped)
+			if len(matched) < 2 {
+				continue
+			}
+			id := fmt.Sprintf("%s:%s:%s", key.repo, key.ref, rule.Id)
+			conflict, ok := conflicts[id]
+			if !ok {
+				conflict = &Conflict{Repo: key.repo, Ref: key.ref, Rule: rule.Id, Description: rule.Description}
+				conflicts[id] = conflict
+				families[id] = map[string]map[string]bool{}
+			}
+			if key.variant != "" {
+				conflict.Variants = append(conflict.Variants, key.variant)
+			}
+			for family, artifacts := range matched {
+				if families[id][family] == nil {
+					families[id][family] = map[string]bool{}
+				}
+				for _, artifact := range artifacts {
+					families[id][family][artifact] = true
+				}
+			}
+		}
+	}
+
+	result := make([]Conflict, 0, len(conflicts))
+	for id, conflict := range conflicts {
+		sort.Strings(conflict.Variants)
+		for family, artifacts := range families[id] {
+			match := Match{Family: family}
+			for artifact := range artifacts {
+				match.Artifacts = append(match.Artifacts, artifact)
+			}
+			sort.Strings(match.Artifacts)
+			conflict.Families = append(conflict.Families, match)
+		}
+		sort.Slice(conflict.Families, func(i, j int) bool { return conflict.Families[i].Family < conflict.Families[j].Family })
+		result = append(result, *conflict)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Repo != result[j].Repo {
+			return result[i].Repo < result[j].Repo
+		}
+		if result[i].Ref != result[j].Ref {
+			return result[i].Ref < result[j].Ref
+		}
+		return result[i].Rule < result[j].Rule
+	})
+	return result
+}
+
+// match returns artifacts of every family found in items by family name
+func (r Rule) match(items []storage.StorageDto) map[string][]string {
+	result := map[string][]string{}
+	for _, family := range r.Families {
+		for _, item := range items {
+			if family.matches(item.Dependency, item.Version) {
+				result[family.Name] = append(result[family.Name], item.Dependency+":"+item.Version)
+			}
+		}
+	}
+	return result
+}
+
+func (r *Rule) compile() error {
+	if len(r.Families) < 2 {
+		return fmt.Errorf("conflict rule %s: at least two families are required", r.Id)
+	}
+	names := map[string]bool{}
+	for i := range r.Families {
+		family := &r.Families[i]
+		if family.Name == "" || names[family.Name] {
+			return fmt.Errorf("conflict rule %s: family #%d needs unique name", r.Id, i+1)
+		}
+		names[family.Name] = true
+		if len(family.Artifacts) == 0 {
+			return fmt.Errorf("conflict rule %s: family %s has no artifacts", r.Id, family.Name)
+		}
+		for _, pattern := range family.Artifacts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("conflict rule %s: malformed pattern %q", r.Id, pattern)
+			}
+		}
+		if family.Versions != "" && family.versions == nil {
+			versions, err := maven.ParseRange(family.Versions)
+			if err != nil {
+				return fmt.Errorf("conflict rule %s: family %s: %s", r.Id, family.Name, err)
+			}
+			family.versions = versions
+		}
+	}
+	return nil
+}
+
+func (f Family) matches(dependency string, version string) bool {
+	if f.versions != nil && !f.versions.Contains(version) {
+		return false
+	}
+	for _, pattern := range f.Artifacts {
+		if matched, _ := path.Match(pattern, dependency); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package conflicts
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"reflect"
+	"testing"
+)
+
+func item(repo string, variant string, dependency string, version string) storage.StorageDto {
+	return storage.StorageDto{Repo: repo, Ref: "main", Variant: variant, Kind: storage.KindLibrary, Dependency: dependency, Version: version}
+}
+
+func TestDetect(t *testing.T) {
+	items := []storage.StorageDto{
+		item("acme/api", "", "org.slf4j:slf4j-api", "1.7.36"),
+		item("acme/api", "", "ch.qos.logback:logback-classic", "1.2.11"),
+		item("acme/api", "", "org.slf4j:slf4j-log4j12", "1.7.36"),
+		item("acme/api", "", "javax.servlet:javax.servlet-api", "4.0.1"),
+		item("acme/api", "", "jakarta.servlet:jakarta.servlet-api", "5.0.0"),
+		item("acme/app", "debug", "org.bouncycastle:bcprov-jdk15on", "1.70"),
+		item("acme/app", "debug", "org.bouncycastle:bcpkix-jdk18on", "1.72"),
+		item("acme/app", "release", "org.bouncycastle:bcprov-jdk18on", "1.72"),
+		item("acme/app", "staging", "org.bouncycastle:bcprov-jdk15on", "1.70"),
+		item("acme/app", "staging", "org.bouncycastle:bcprov-jdk18on", "1.72"),
+		item("acme/web", "", "log4j:log4j", "1.2.17"),
+	}
+	result := Detect(Rules(nil), items)
+
+	expected := []Conflict{
+		{
+			Repo: "acme/api", Ref: "main", Rule: "javax-jakarta-servlet",
+			Families: []Match{
+				{Family: "jakarta", Artifacts: []string{"jakarta.servlet:jakarta.servlet-api:5.0.0"}},
+				{Family: "javax", Artifacts: []string{"javax.servlet:javax.servlet-api:4.0.1"}},
+			},
+		},
+		{
+			Repo: "acme/api", Ref: "main", Rule: "slf4j-bindings",
+			Families: []Match{
+				{Family: "log4j12", Artifacts: []string{"org.slf4j:slf4j-log4j12:1.7.36"}},
+				{Family: "logback", Artifacts: []string{"ch.qos.logback:logback-classic:1.2.11"}},
+			},
+		},
+		{
+			Repo: "acme/app", Ref: "main", Rule: "bouncycastle-jdk-variants", Variants: []string{"debug", "staging"},
+			Families: []Match{
+				{Family: "jdk15on", Artifacts: []string{"org.bouncycastle:bcprov-jdk15on:1.70"}},
+				{Family: "jdk18on", Artifacts: []string{"org.bouncycastle:bcpkix-jdk18on:1.72", "org.bouncycastle:bcprov-jdk18on:1.72"}},
+			},
+		},
+	}
+	for i := range result {
+		result[i].Description = ""
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Wrong conflicts\n%+v\nexpected\n%+v", result, expected)
+	}
+}
+
+func TestDetectVersions(t *testing.T) {
+	rules := []Rule{{
+		Id: "old-and-new",
+		Families: []Family{
+			{Name: "old", Artifacts: []string{"com.acme:*"}, Versions: "(,2.0)"},
+			{Name: "new", Artifacts: []string{"com.acme:*"}, Versions: "[2.0,)"},
+		},
+	}}
+	mixed := []storage.StorageDto{item("acme/api", "", "com.acme:core", "1.5"), item("acme/api", "", "com.acme:client", "2.1")}
+	if result := Detect(rules, mixed); len(result) != 1 {
+		t.Errorf("Expected conflict of versions, got %+v", result)
+	}
+	aligned := []storage.StorageDto{item("acme/api", "", "com.acme:core", "2.0"), item("acme/api", "", "com.acme:client", "2.1")}
+	if result := Detect(rules, aligned); len(result) != 0 {
+		t.Errorf("Expected no conflicts, got %+v", result)
+	}
+}
+
+func TestRules(t *testing.T) {
+	user := []Rule{
+		{Id: "guava-google-collections", Disabled: true},
+		{Id: "slf4j-bindings", Families: []Family{
+			{Name: "logback", Artifacts: []string{"ch.qos.logback:logback-classic"}},
+			{Name: "log4j2", Artifacts: []string{"org.apache.logging.log4j:log4j-slf4j-impl"}},
+		}},
+		{Id: "json", Families: []Family{
+			{Name: "gson", Artifacts: []string{"com.google.code.gson:gson"}},
+			{Name: "jackson", Artifacts: []string{"com.fasterxml.jackson.core:jackson-databind"}},
+		}},
+	}
+	rules := Rules(user)
+	if len(rules) != len(Builtin()) {
+		t.Fatalf("Expected %d rules, got %d", len(Builtin()), len(rules))
+	}
+	byId := map[string]Rule{}
+	for _, rule := range rules {
+		byId[rule.Id] = rule
+	}
+	if _, ok := byId["guava-google-collections"]; ok {
+		t.Error("Disabled rule is returned")
+	}
+	if len(byId["slf4j-bindings"].Families) != 2 {
+		t.Errorf("Built-in rule is not replaced: %+v", byId["slf4j-bindings"])
+	}
+	if rules[len(rules)-1].Id != "json" {
+		t.Errorf("User rule should be last, got %s", rules[len(rules)-1].Id)
+	}
+
+	if err := Validate(Builtin()); err != nil {
+		t.Errorf("Built-in rules are invalid: %s", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	family := Family{Name: "a", Artifacts: []string{"a:b"}}
+	malformed := [][]Rule{
+		{{Families: []Family{family, {Name: "c", Artifacts: []string{"c:d"}}}}},
+		{{Id: "x", Disabled: true}, {Id: "x", Disabled: true}},
+		{{Id: "x", Families: []Family{family}}},
+		{{Id: "x", Families: []Family{family, family}}},
+		{{Id: "x", Families: []Family{family, {Name: "c"}}}},
+		{{Id: "x", Families: []Family{family, {Name: "c", Artifacts: []string{"c:[d"}}}}},
+		{{Id: "x", Families: []Family{family, {Name: "c", Artifacts: []string{"c:d"}, Versions: "[1.0"}}}},
+	}
+	for _, rules := range malformed {
+		if err := Validate(rules); err == nil {
+			t.Errorf("Expected error for %+v", rules)
+		}
+	}
+	if err := Validate([]Rule{{Id: "x", Disabled: true}}); err != nil {
+		t.Errorf("Disabled rule needs no families: %s", err)
+	}
+}
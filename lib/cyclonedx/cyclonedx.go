@@ -0,0 +1,147 @@
+package cyclonedx
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ContentType is media type of CycloneDX JSON documents
+const ContentType = "application/vnd.cyclonedx+json"
+
+type (
+	Bom struct {
+		BomFormat    string       `json:"bomFormat"`
+		SpecVersion  string       `json:"specVersion"`
+		Metadata     Metadata     `json:"metadata"`
+		Components   []Component  `json:"components"`
+		Dependencies []Dependency `json:"dependencies"`
+	}
+
+	Metadata struct {
+		Component *Component `json:"component"`
+	}
+
+	Component struct {
+		Type       string          `json:"type"`
+		BomRef     string          `json:"bom-ref"`
+		Group      string          `json:"group"`
+		Name       string          `json:"name"`
+		Version    string          `json:"version"`
+		Purl       string          `json:"purl"`
+		Scope      string          `json:"scope"`
+		Licenses   []LicenseChoice `json:"licenses"`
+		Components []Component     `json:"components"`
+	}
+
+	LicenseChoice struct {
+		License *struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	}
+
+	Dependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn"`
+	}
+)
+
+// Parse converts CycloneDX JSON BOM to upload payload, only Maven components are kept
+func Parse(reader io.Reader) (*storage.DependenciesRest, error) {
+	var bom Bom
+	if err := json.NewDecoder(reader).Decode(&bom); err != nil {
+		return nil, errors.Wrap(err, "malformed CycloneDX document")
+	}
+	if bom.BomFormat != "CycloneDX" {
+		return nil, fmt.Errorf("unexpected bomFormat %q, CycloneDX JSON is expected", bom.BomFormat)
+	}
+
+	deps := &storage.DependenciesRest{}
+	refs := map[string]string{}
+	var visit func(components []Component)
+	visit = func(components []Component) {
+		for _, component := range components {
+			visit(component.Components)
+			if component.Type != "" && component.Type != "library" && component.Type != "framework" {
+				continue
+			}
+			group, name, version, ok := coordinates(component)
+			if !ok || component.Scope == "excluded" {
+				continue
+			}
+			dep := storage.DependencyRest{Group: group, Name: name, Version: version}
+			for _, choice := range component.Licenses {
+				switch {
+				case choice.Expression != "":
+					dep.Licenses = append(dep.Licenses, licenses.Split(choice.Expression)...)
+				case choice.License != nil && choice.License.Id != "":
+					dep.Licenses = append(dep.Licenses, choice.License.Id)
+				case choice.License != nil && choice.License.Name != "":
+					dep.Licenses = append(dep.Licenses, licenses.Normalize(choice.License.Name))
+				}
+			}
+			if component.BomRef != "" {
+				refs[component.BomRef] = group + ":" + name
+			}
+			deps.Dependencies = append(deps.Dependencies, dep)
+		}
+	}
+	visit(bom.Components)
+
+	// BOM of published library describes artifact produced by repository
+	var root string
+	if component := bom.Metadata.Component; component != nil {
+		root = component.BomRef
+		if group, name, _, ok := coordinates(*component); ok && (component.Type == "library" || component.Type == "framework") {
+			deps.Produces = append(deps.Produces, group+":"+name)
+		}
+	}
+	for _, dependency := range bom.Dependencies {
+		from, known := refs[dependency.Ref]
+		if !known && (dependency.Ref != root || root == "") {
+			continue
+		}
+		for _, ref := range dependency.DependsOn {
+			if to, ok := refs[ref]; ok {
+				deps.Edges = append(deps.Edges, storage.EdgeRest{From: from, To: to})
+			}
+		}
+	}
+	return deps, nil
+}
+
+// coordinates takes group, name and version from component or its pkg:maven purl
+func coordinates(component Component) (string, string, string, bool) {
+	if component.Purl != "" {
+		if !strings.HasPrefix(component.Purl, "pkg:maven/") {
+			return "", "", "", false
+		}
+		purl := strings.TrimPrefix(component.Purl, "pkg:maven/")
+		purl = strings.SplitN(strings.SplitN(purl, "?", 2)[0], "#", 2)[0]
+		if at := strings.LastIndex(purl, "@"); at > 0 {
+			if version, err := url.PathUnescape(purl[at+1:]); err == nil && component.Version == "" {
+				component.Version = version
+			}
+			purl = purl[:at]
+		}
+		if parts := strings.Split(purl, "/"); len(parts) == 2 {
+			if component.Group == "" {
+				component.Group, _ = url.PathUnescape(parts[0])
+			}
+			if component.Name == "" {
+				component.Name, _ = url.PathUnescape(parts[1])
+			}
+		}
+	}
+	if component.Group == "" || component.Name == "" || component.Version == "" {
+		return "", "", "", false
+	}
+	return component.Group, component.Name, component.Version, true
+}
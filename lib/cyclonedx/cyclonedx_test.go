@@ -0,0 +1,60 @@
+package cyclonedx
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	file, err := os.Open("testdata/bom.cdx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	deps, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []storage.DependencyRest{
+		{Group: "com.google.guava", Name: "guava", Version: "31.1-jre", Licenses: []string{"Apache-2.0"}},
+		{Group: "com.google.guava", Name: "failureaccess", Version: "1.0.1", Licenses: []string{"Apache-2.0"}},
+		{Group: "com.h2database", Name: "h2", Version: "2.1.214", Licenses: []string{"MPL-2.0 OR EPL-1.0"}},
+	}
+	if !reflect.DeepEqual(deps.Dependencies, expected) {
+		t.Errorf("Wrong dependencies %+v", deps.Dependencies)
+	}
+
+	edges := []storage.EdgeRest{
+		{From: "", To: "com.google.guava:guava"},
+		{From: "", To: "com.h2database:h2"},
+		{From: "com.google.guava:guava", To: "com.google.guava:failureaccess"},
+	}
+	if !reflect.DeepEqual(deps.Edges, edges) {
+		t.Errorf("Wrong edges %+v", deps.Edges)
+	}
+	if deps.Produces != nil {
+		t.Errorf("Application BOM should not produce artifacts, got %v", deps.Produces)
+	}
+}
+
+func TestParseLibraryProduces(t *testing.T) {
+	bom := `{"bomFormat": "CycloneDX", "metadata": {"component": {"type": "library", "purl": "pkg:maven/com.acme/client@1.2.0"}}}`
+	deps, err := Parse(strings.NewReader(bom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(deps.Produces, []string{"com.acme:client"}) {
+		t.Errorf("Wrong produces %v", deps.Produces)
+	}
+}
+
+func TestParseNotCycloneDX(t *testing.T) {
+	if _, err := Parse(strings.NewReader(`{"dependencies": []}`)); err == nil {
+		t.Error("Expected error for upload payload")
+	}
+}
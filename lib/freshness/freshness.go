@@ -0,0 +1,155 @@
+package freshness
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout is layout of snapshot dates, one snapshot per repo/ref and day
+const DateLayout = "2006-01-02"
+
+const hoursPerYear = 365.25 * 24
+
+// Dashboard is JSON of /freshness: the most recent snapshot of every org and repo/ref with daily trend
+type Dashboard struct {
+	Org   string                 `json:"org,omitempty"`
+	Orgs  []storage.FreshnessDto `json:"orgs"`
+	Refs  []storage.FreshnessDto `json:"refs"`
+	Trend []storage.FreshnessDto `json:"trend"`
+}
+
+// Measure computes snapshot of repo/ref items annotated by outdated.Resolver, every dependency version is counted once
+// regardless of variants. Dependencies without release times of both used and latest versions add no libyears
+func Measure(repo string, ref string, items []storage.StorageDto, date time.Time) storage.FreshnessDto {
+	snapshot := storage.FreshnessDto{
+		Id:   fmt.Sprintf("%s:%s", repo, ref),
+		Date: date.UTC().Format(DateLayout),
+		Repo: repo,
+		Ref:  ref,
+	}
+
+	seen := map[string]bool{}
+	for _, item := range items {
+		if item.Kind != "" && item.Kind != storage.KindLibrary && item.Kind != storage.KindPlatform {
+			continue
+		}
+		key := storage.VersionKey(item.Dependency, item.Version)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		snapshot.Dependencies++
+		if item.Outdated == nil {
+			continue
+		}
+		snapshot.Resolved++
+		if item.Outdated.Behind == 0 {
+			snapshot.Current++
+		}
+		snapshot.Libyears += Libyears(item.Outdated)
+	}
+	snapshot.CurrentShare = share(snapshot.Current, snapshot.Resolved)
+	return snapshot
+}
+
+// Libyears returns time between releases of used and latest versions, zero when either is unknown
+func Libyears(outdated *storage.OutdatedDto) float64 {
+	released, errReleased := time.Parse(time.RFC3339, outdated.Released)
+	latest, errLatest := time.Parse(time.RFC3339, outdated.LatestReleased)
+	if errReleased != nil || errLatest != nil || !latest.After(released) {
+		return 0
+	}
+	return latest.Sub(released).Hours() / hoursPerYear
+}
+
+// Summarize adds snapshots up into single one with given id, e.g. org
+func Summarize(id string, date string, snapshots []storage.FreshnessDto) storage.FreshnessDto {
+	summary := storage.FreshnessDto{Id: id, Date: date}
+	for _, snapshot := range snapshots {
+		summary.Dependencies += snapshot.Dependencies
+		summary.Resolved += snapshot.Resolved
+		summary.Current += snapshot.Current
+		summary.Libyears += snapshot.Libyears
+	}
+	summary.CurrentShare = share(summary.Current, summary.Resolved)
+	return summary
+}
+
+// Latest returns the most recent snapshot of every repo/ref sorted by libyears, most stale first
+func Latest(snapshots []storage.FreshnessDto) []storage.FreshnessDto {
+	latest := map[string]storage.FreshnessDto{}
+	for _, snapshot := range snapshots {
+		if current, ok := latest[snapshot.Id]; !ok || snapshot.Date > current.Date {
+			latest[snapshot.Id] = snapshot
+		}
+	}
+
+	result := make([]storage.FreshnessDto, 0, len(latest))
+	for _, snapshot := range latest {
+		result = append(result, snapshot)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Libyears != result[j].Libyears {
+			return result[i].Libyears > result[j].Libyears
+		}
+		return result[i].Id < result[j].Id
+	})
+	return result
+}
+
+// Orgs summarizes the most recent snapshots per org, the first part of repo
+func Orgs(snapshots []storage.FreshnessDto) []storage.FreshnessDto {
+	byOrg := map[string][]storage.FreshnessDto{}
+	dates := map[string]string{}
+	for _, snapshot := range Latest(snapshots) {
+		org := Org(snapshot.Repo)
+		byOrg[org] = append(byOrg[org], snapshot)
+		if snapshot.Date > dates[org] {
+			dates[org] = snapshot.Date
+		}
+	}
+
+	result := make([]storage.FreshnessDto, 0, len(byOrg))
+	for org, items := range byOrg {
+		result = append(result, Summarize(org, dates[org], items))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Id < result[j].Id })
+	return result
+}
+
+// Trend summarizes snapshots for every date they were taken on. Repo/ref missing on some date is counted
+// with its previous snapshot, so trend does not drop when scheduled snapshot of single repo fails
+func Trend(id string, snapshots []storage.FreshnessDto) []storage.FreshnessDto {
+	sorted := append([]storage.FreshnessDto{}, snapshots...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	var result []storage.FreshnessDto
+	current := map[string]storage.FreshnessDto{}
+	for idx, snapshot := range sorted {
+		current[snapshot.Id] = snapshot
+		if idx+1 < len(sorted) && sorted[idx+1].Date == snapshot.Date {
+			continue
+		}
+		items := make([]storage.FreshnessDto, 0, len(current))
+		for _, item := range current {
+			items = append(items, item)
+		}
+		result = append(result, Summarize(id, snapshot.Date, items))
+	}
+	return result
+}
+
+func Org(repo string) string {
+	return strings.SplitN(repo, "/", 2)[0]
+}
+
+func share(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,77 @@
+package freshness
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMeasure(t *testing.T) {
+	items := []storage.StorageDto{
+		{Dependency: "com.google.guava:guava", Version: "31.1-jre", Kind: storage.KindLibrary, Variant: "debug", Outdated: &storage.OutdatedDto{
+			Latest: "33.0.0-jre", Behind: 3, Released: "2022-02-28T00:00:00Z", LatestReleased: "2023-12-18T00:00:00Z",
+		}},
+		{Dependency: "com.google.guava:guava", Version: "31.1-jre", Kind: storage.KindLibrary, Variant: "release", Outdated: &storage.OutdatedDto{
+			Latest: "33.0.0-jre", Behind: 3, Released: "2022-02-28T00:00:00Z", LatestReleased: "2023-12-18T00:00:00Z",
+		}},
+		{Dependency: "org.slf4j:slf4j-api", Version: "2.0.9", Kind: storage.KindLibrary, Outdated: &storage.OutdatedDto{Latest: "2.0.9"}},
+		{Dependency: "com.squareup.okhttp3:okhttp", Version: "3.0.0", Kind: storage.KindLibrary, Outdated: &storage.OutdatedDto{Latest: "4.12.0", Behind: 40}},
+		{Dependency: "com.example:internal", Version: "1.0", Kind: storage.KindLibrary},
+		{Dependency: "org.jetbrains.kotlin.jvm", Version: "1.9.0", Kind: storage.KindPlugin},
+	}
+
+	snapshot := Measure("acme/app", "main", items, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))
+	if snapshot.Id != "acme/app:main" || snapshot.Date != "2024-01-31" {
+		t.Errorf("Unexpected snapshot key %s %s", snapshot.Id, snapshot.Date)
+	}
+	if snapshot.Dependencies != 4 || snapshot.Resolved != 3 || snapshot.Current != 1 {
+		t.Errorf("Unexpected counts %+v", snapshot)
+	}
+	if math.Abs(snapshot.CurrentShare-1.0/3) > 1e-9 {
+		t.Errorf("Unexpected current share %f", snapshot.CurrentShare)
+	}
+	if math.Abs(snapshot.Libyears-1.8) > 0.01 {
+		t.Errorf("Expected 1.8 libyears, got %f", snapshot.Libyears)
+	}
+}
+
+func TestOrgsAndTrend(t *testing.T) {
+	snapshots := []storage.FreshnessDto{
+		{Id: "acme/app:main", Repo: "acme/app", Ref: "main", Date: "2024-01-01", Resolved: 10, Current: 5, Libyears: 4},
+		{Id: "acme/app:main", Repo: "acme/app", Ref: "main", Date: "2024-01-02", Resolved: 10, Current: 8, Libyears: 2},
+		{Id: "acme/lib:main", Repo: "acme/lib", Ref: "main", Date: "2024-01-01", Resolved: 10, Current: 10, Libyears: 0},
+		{Id: "other/app:main", Repo: "other/app", Ref: "main", Date: "2024-01-02", Resolved: 4, Current: 1, Libyears: 6},
+	}
+
+	orgs := Orgs(snapshots)
+	if len(orgs) != 2 || orgs[0].Id != "acme" || orgs[1].Id != "other" {
+		t.Fatalf("Unexpected orgs %+v", orgs)
+	}
+	if orgs[0].Libyears != 2 || orgs[0].Current != 18 || orgs[0].CurrentShare != 0.9 || orgs[0].Date != "2024-01-02" {
+		t.Errorf("Unexpected acme summary %+v", orgs[0])
+	}
+
+	latest := Latest(snapshots)
+	if len(latest) != 3 || latest[0].Id != "other/app:main" || latest[1].Date != "2024-01-02" {
+		t.Errorf("Unexpected latest snapshots %+v", latest)
+	}
+
+	// acme/lib has no snapshot on 2024-01-02, its previous one is still counted
+	trend := Trend("*", snapshots)
+	if len(trend) != 2 {
+		t.Fatalf("Unexpected trend %+v", trend)
+	}
+	if trend[0].Libyears != 4 || trend[0].Current != 15 {
+		t.Errorf("Unexpected first day %+v", trend[0])
+	}
+	if trend[1].Libyears != 8 || trend[1].Current != 19 || trend[1].Resolved != 24 {
+		t.Errorf("Unexpected second day %+v", trend[1])
+	}
+}
+
+func TestLibyearsUnknown(t *testing.T) {
+	if years := Libyears(&storage.OutdatedDto{Released: "2022-02-28T00:00:00Z"}); years != 0 {
+		t.Errorf("Expected 0 without latest release time, got %f", years)
+	}
+}
@@ -0,0 +1,98 @@
+package gradle
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLockfile(t *testing.T) {
+	lockfile := `# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:guava:32.1.2-jre=compileClasspath,runtimeClasspath
+org.slf4j:slf4j-api:2.0.9=runtimeClasspath
+empty=annotationProcessor
+`
+	deps, err := ParseLockfile(strings.NewReader(lockfile))
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	if len(deps.Dependencies) != 2 {
+		t.Fatalf("Wrong dependencies count %d", len(deps.Dependencies))
+	}
+
+	guava := deps.Dependencies[0]
+	if guava.Group != "com.google.guava" || guava.Name != "guava" || guava.Version != "32.1.2-jre" {
+		t.Error("Wrong dependency", guava)
+	}
+	if len(guava.Configurations) != 2 || guava.Configurations[1] != "runtimeClasspath" {
+		t.Error("Wrong configurations", guava.Configurations)
+	}
+	if guava.Location == nil || guava.Location.Line != 4 {
+		t.Error("Wrong location", guava.Location)
+	}
+}
+
+func TestParseLockfileMalformed(t *testing.T) {
+	if _, err := ParseLockfile(strings.NewReader("com.google.guava:guava=runtimeClasspath\n")); err == nil {
+		t.Error("Error is nil")
+	}
+}
+
+func TestParseDependencyReport(t *testing.T) {
+	file, err := os.Open("testdata/dependencies.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	deps, err := ParseDependencyReport(file)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	versions := make(map[string]storage.DependencyRest)
+	for _, dep := range deps.Dependencies {
+		versions[dep.Group+":"+dep.Name] = dep
+	}
+
+	expected := map[string]string{
+		"org.springframework.boot:spring-boot-dependencies": "3.1.5",
+		"com.google.guava:guava":                            "32.1.2-jre",
+		"org.springframework.boot:spring-boot-starter-web":  "3.1.5",
+		"org.springframework:spring-web":                    "6.0.13",
+		"org.springframework:spring-core":                   "6.0.13",
+		"com.fasterxml.jackson.core:jackson-databind":       "2.15.3",
+	}
+	if len(versions) != len(expected) {
+		t.Errorf("Wrong dependencies %+v", deps.Dependencies)
+	}
+	for dep, version := range expected {
+		if versions[dep].Version != version {
+			t.Errorf("Wrong version of %s: %s, expected %s", dep, versions[dep].Version, version)
+		}
+	}
+	if configurations := versions["org.springframework:spring-core"].Configurations; len(configurations) != 2 {
+		t.Error("Wrong configurations", configurations)
+	}
+
+	edges := make(map[storage.EdgeRest]bool)
+	for _, edge := range deps.Edges {
+		edges[edge] = true
+	}
+	for _, edge := range []storage.EdgeRest{
+		{From: "", To: "org.springframework.boot:spring-boot-starter-web"},
+		{From: "", To: "com.google.guava:guava"},
+		{From: "org.springframework.boot:spring-boot-starter-web", To: "org.springframework:spring-web"},
+		{From: "org.springframework:spring-web", To: "org.springframework:spring-core"},
+	} {
+		if !edges[edge] {
+			t.Errorf("Edge %+v not found", edge)
+		}
+	}
+	if edges[storage.EdgeRest{From: "org.springframework.boot:spring-boot-dependencies", To: "org.springframework:spring-web"}] {
+		t.Error("Constraint is reported as edge")
+	}
+}
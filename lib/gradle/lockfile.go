@@ -0,0 +1,55 @@
+package gradle
+
+import (
+	"bufio"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"strings"
+)
+
+// ParseLockfile reads gradle.lockfile / settings-gradle.lockfile produced by dependency locking:
+//
+//	com.google.guava:guava:32.1.2-jre=compileClasspath,runtimeClasspath
+//	empty=annotationProcessor
+//
+// Dependencies get location with line only, caller knows lockfile path.
+func ParseLockfile(reader io.Reader) (*storage.DependenciesRest, error) {
+	var result storage.DependenciesRest
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "empty=") {
+			continue
+		}
+
+		coordinates := text
+		var configurations []string
+		if idx := strings.Index(text, "="); idx >= 0 {
+			coordinates = text[:idx]
+			if text[idx+1:] != "" {
+				configurations = strings.Split(text[idx+1:], ",")
+			}
+		}
+
+		parts := strings.Split(coordinates, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("line %d: expected group:name:version, got %q", line, coordinates)
+		}
+		result.Dependencies = append(result.Dependencies, storage.DependencyRest{
+			Group:          parts[0],
+			Name:           parts[1],
+			Version:        parts[2],
+			Configurations: configurations,
+			Location:       &storage.LocationRest{Line: line},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,146 @@
+package gradle
+
+import (
+	"bufio"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	reportConfiguration = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)( - .*)?$`)
+	reportDependency    = regexp.MustCompile(`^((?:[|\s]    )*)[+\\]--- (.+)$`)
+)
+
+// ParseDependencyReport reads output of `gradle dependencies` task, keeping resolved versions,
+// configurations and edges between dependencies:
+//
+//	runtimeClasspath - Runtime classpath of source set 'main'.
+//	+--- org.springframework.boot:spring-boot-starter-web -> 3.1.5
+//	|    \--- org.springframework:spring-web:6.0.13
+//	\--- com.google.guava:guava:31.0-jre -> 32.1.2-jre (*)
+func ParseDependencyReport(reader io.Reader) (*storage.DependenciesRest, error) {
+	var result storage.DependenciesRest
+
+	index := make(map[string]int)
+	edges := make(map[storage.EdgeRest]bool)
+
+	var configuration string
+	var stack []string
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " ")
+
+		match := reportDependency.FindStringSubmatch(text)
+		if match == nil {
+			if m := reportConfiguration.FindStringSubmatch(text); m != nil {
+				configuration = m[1]
+			} else if text == "" {
+				configuration = ""
+			}
+			stack = stack[:0]
+			continue
+		}
+		if configuration == "" {
+			return nil, fmt.Errorf("line %d: dependency outside of configuration", line)
+		}
+
+		depth := len(match[1]) / 5
+		if depth > len(stack) {
+			return nil, fmt.Errorf("line %d: broken dependency tree indentation", line)
+		}
+		stack = stack[:depth]
+
+		group, name, version, ok := parseReportEntry(match[2])
+		if !ok {
+			// project dependencies, constraints and unresolved entries are not part of the graph,
+			// children of such entries are attached to the nearest resolved parent
+			stack = append(stack, parentOf(stack))
+			continue
+		}
+
+		coordinate := fmt.Sprintf("%s:%s", group, name)
+		edges[storage.EdgeRest{From: parentOf(stack), To: coordinate}] = true
+		stack = append(stack, coordinate)
+
+		key := fmt.Sprintf("%s:%s", coordinate, version)
+		if idx, found := index[key]; found {
+			dep := &result.Dependencies[idx]
+			if !contains(dep.Configurations, configuration) {
+				dep.Configurations = append(dep.Configurations, configuration)
+			}
+			continue
+		}
+		index[key] = len(result.Dependencies)
+		result.Dependencies = append(result.Dependencies, storage.DependencyRest{
+			Group:          group,
+			Name:           name,
+			Version:        version,
+			Configurations: []string{configuration},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for edge := range edges {
+		result.Edges = append(result.Edges, edge)
+	}
+
+	return &result, nil
+}
+
+func parseReportEntry(entry string) (string, string, string, bool) {
+	for _, suffix := range []string{" (*)", " (c)", " (n)", " FAILED"} {
+		if strings.HasSuffix(entry, suffix) {
+			if suffix != " (*)" {
+				return "", "", "", false
+			}
+			entry = strings.TrimSuffix(entry, suffix)
+		}
+	}
+	if strings.HasPrefix(entry, "project ") {
+		return "", "", "", false
+	}
+
+	var resolved string
+	if idx := strings.Index(entry, " -> "); idx >= 0 {
+		resolved = entry[idx+4:]
+		entry = entry[:idx]
+	}
+
+	parts := strings.Split(entry, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", "", "", false
+	}
+	version := resolved
+	if version == "" && len(parts) == 3 {
+		version = parts[2]
+	}
+	if version == "" || strings.HasPrefix(version, "project ") {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[1], version, true
+}
+
+func parentOf(stack []string) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	return stack[len(stack)-1]
+}
+
+func contains(values []string, value string) bool {
+	for _, val := range values {
+		if val == value {
+			return true
+		}
+	}
+	return false
+}
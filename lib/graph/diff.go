@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+type Change struct {
+	Change     string `json:"change"`
+	Kind       string `json:"kind"`
+	Dependency string `json:"dependency"`
+	Variant    string `json:"variant,omitempty"`
+	Before     string `json:"before,omitempty"`
+	After      string `json:"after,omitempty"`
+}
+
+// Diff compares two dependency sets of repo/ref, e.g. two refs of the same repository
+func Diff(before []storage.StorageDto, after []storage.StorageDto) []Change {
+	type key struct {
+		kind       string
+		dependency string
+		variant    string
+	}
+	keyOf := func(item storage.StorageDto) key {
+		kind := item.Kind
+		if kind == "" {
+			kind = storage.KindLibrary
+		}
+		return key{kind, item.Dependency, item.Variant}
+	}
+
+	old := make(map[key]string, len(before))
+	for _, item := range before {
+		old[keyOf(item)] = item.Version
+	}
+
+	var result []Change
+	seen := make(map[key]bool, len(after))
+	for _, item := range after {
+		k := keyOf(item)
+		seen[k] = true
+		version, found := old[k]
+		switch {
+		case !found:
+			result = append(result, Change{Change: ChangeAdded, Kind: k.kind, Dependency: k.dependency, Variant: k.variant, After: item.Version})
+		case version != item.Version:
+			result = append(result, Change{Change: ChangeChanged, Kind: k.kind, Dependency: k.dependency, Variant: k.variant, Before: version, After: item.Version})
+		}
+	}
+	for k, version := range old {
+		if !seen[k] {
+			result = append(result, Change{Change: ChangeRemoved, Kind: k.kind, Dependency: k.dependency, Variant: k.variant, Before: version})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Dependency != result[j].Dependency {
+			return result[i].Dependency < result[j].Dependency
+		}
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Variant < result[j].Variant
+	})
+
+	return result
+}
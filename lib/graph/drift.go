@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+)
+
+type Drift struct {
+	Dependency string         `json:"dependency"`
+	Latest     string         `json:"latest"`
+	Versions   []VersionUsage `json:"versions"`
+}
+
+type VersionUsage struct {
+	Version string   `json:"version"`
+	Repos   []string `json:"repos"`
+}
+
+// Drifts groups usages by dependency and reports dependencies used with more than one version,
+// versions are ordered from the newest one
+func Drifts(items []storage.StorageDto) []Drift {
+	usages := make(map[string]map[string][]string)
+	for _, item := range items {
+		if _, ok := usages[item.Dependency]; !ok {
+			usages[item.Dependency] = make(map[string][]string)
+		}
+		usages[item.Dependency][item.Version] = append(usages[item.Dependency][item.Version], item.Repo+"/"+item.Ref)
+	}
+
+	var result []Drift
+	for dependency, versions := range usages {
+		if len(versions) < 2 {
+			continue
+		}
+
+		drift := Drift{Dependency: dependency}
+		for version, repos := range versions {
+			sort.Strings(repos)
+			drift.Versions = append(drift.Versions, VersionUsage{Version: version, Repos: repos})
+		}
+		sort.Slice(drift.Versions, func(i, j int) bool {
+			return maven.CompareVersions(drift.Versions[i].Version, drift.Versions[j].Version) > 0
+		})
+		drift.Latest = drift.Versions[0].Version
+		result = append(result, drift)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Dependency < result[j].Dependency
+	})
+
+	return result
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/archive"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"sort"
+)
+
+// Graph keeps exported tables in memory and answers the same queries as the service
+type Graph struct {
+	byRepo       map[string][]storage.StorageDto
+	byDependency map[string][]storage.StorageDto
+	children     map[string][]string
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		byRepo:       make(map[string][]storage.StorageDto),
+		byDependency: make(map[string][]storage.StorageDto),
+		children:     make(map[string][]string),
+	}
+}
+
+// Load reads archive produced by export
+func Load(reader *archive.Reader) (*Graph, error) {
+	g := NewGraph()
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch record.Table {
+		case archive.TableStorage:
+			var item storage.StorageDto
+			if err := record.Decode(&item); err != nil {
+				return nil, err
+			}
+			g.AddItem(item)
+		case archive.TableDependencies:
+			var item storage.DependencyDto
+			if err := record.Decode(&item); err != nil {
+				return nil, err
+			}
+			g.children[item.Parent] = append(g.children[item.Parent], item.Child)
+		}
+	}
+	return g, nil
+}
+
+func (g *Graph) AddItem(item storage.StorageDto) {
+	key := fmt.Sprintf("%s:%s", item.Repo, item.Ref)
+	g.byRepo[key] = append(g.byRepo[key], item)
+	g.byDependency[item.Dependency] = append(g.byDependency[item.Dependency], item)
+}
+
+func (g *Graph) Dependencies(repo string, ref string, filter *storage.StorageFilter) ([]storage.StorageDto, error) {
+	return filtered(g.byRepo[fmt.Sprintf("%s:%s", repo, ref)], filter, nil), nil
+}
+
+func (g *Graph) WhoUses(dependency string, version *string, filter *storage.StorageFilter) ([]storage.StorageDto, error) {
+	return filtered(g.byDependency[dependency], filter, version), nil
+}
+
+func (g *Graph) DependencyNames(group string) ([]string, error) {
+	names := append([]string{}, g.children[group]...)
+	sort.Strings(names)
+	return names, nil
+}
+
+func filtered(items []storage.StorageDto, filter *storage.StorageFilter, version *string) []storage.StorageDto {
+	result := make([]storage.StorageDto, 0, len(items))
+	for _, item := range items {
+		if filter.Match(item) && (version == nil || item.Version == *version) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"bytes"
+	"gradle-serverless-dependencies-graph/lib/archive"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := []storage.StorageDto{
+		{Dependency: "com.google.guava:guava", Version: "31.0-jre"},
+		{Dependency: "org.slf4j:slf4j-api", Version: "2.0.9"},
+		{Dependency: "org.springframework.boot", Version: "3.1.5", Kind: storage.KindPlugin},
+	}
+	after := []storage.StorageDto{
+		{Dependency: "com.google.guava:guava", Version: "32.1.2-jre", Kind: storage.KindLibrary},
+		{Dependency: "org.springframework.boot", Version: "3.1.5", Kind: storage.KindPlugin},
+		{Dependency: "io.micrometer:micrometer-core", Version: "1.11.5"},
+	}
+
+	changes := Diff(before, after)
+	expected := []Change{
+		{Change: ChangeChanged, Kind: storage.KindLibrary, Dependency: "com.google.guava:guava", Before: "31.0-jre", After: "32.1.2-jre"},
+		{Change: ChangeAdded, Kind: storage.KindLibrary, Dependency: "io.micrometer:micrometer-core", After: "1.11.5"},
+		{Change: ChangeRemoved, Kind: storage.KindLibrary, Dependency: "org.slf4j:slf4j-api", Before: "2.0.9"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Wrong changes %+v", changes)
+	}
+	for idx := range expected {
+		if changes[idx] != expected[idx] {
+			t.Errorf("Wrong change %+v, expected %+v", changes[idx], expected[idx])
+		}
+	}
+}
+
+func TestDrifts(t *testing.T) {
+	items := []storage.StorageDto{
+		{Dependency: "com.google.guava:guava", Version: "31.0-jre", Repo: "acme/a", Ref: "main"},
+		{Dependency: "com.google.guava:guava", Version: "32.1.2-jre", Repo: "acme/b", Ref: "main"},
+		{Dependency: "com.google.guava:guava", Version: "32.1.2-jre", Repo: "acme/c", Ref: "main"},
+		{Dependency: "com.google.guava:guava", Version: "9.0", Repo: "acme/d", Ref: "main"},
+		{Dependency: "org.slf4j:slf4j-api", Version: "2.0.9", Repo: "acme/a", Ref: "main"},
+	}
+
+	drifts := Drifts(items)
+	if len(drifts) != 1 {
+		t.Fatalf("Wrong drifts %+v", drifts)
+	}
+	if drifts[0].Latest != "32.1.2-jre" || len(drifts[0].Versions) != 3 || drifts[0].Versions[2].Version != "9.0" {
+		t.Errorf("Wrong drift %+v", drifts[0])
+	}
+	if len(drifts[0].Versions[0].Repos) != 2 {
+		t.Errorf("Wrong repos %+v", drifts[0].Versions[0])
+	}
+}
+
+func TestPaths(t *testing.T) {
+	items := []storage.StorageDto{
+		{Dependency: "org.springframework.boot:spring-boot-starter-web", Direct: true},
+		{Dependency: "org.springframework:spring-web", Parents: []string{"org.springframework.boot:spring-boot-starter-web"}},
+		{Dependency: "com.example:client", Direct: true},
+		{Dependency: "org.springframework:spring-core", Parents: []string{"org.springframework:spring-web", "com.example:client"}},
+	}
+
+	paths := Paths(items, "org.springframework:spring-core")
+	if len(paths) != 2 {
+		t.Fatalf("Wrong paths %v", paths)
+	}
+	if len(paths[0]) != 2 || paths[0][0] != "com.example:client" {
+		t.Errorf("Wrong shortest path %v", paths[0])
+	}
+	if len(paths[1]) != 3 || paths[1][0] != "org.springframework.boot:spring-boot-starter-web" || paths[1][2] != "org.springframework:spring-core" {
+		t.Errorf("Wrong path %v", paths[1])
+	}
+
+	if paths := Paths(items, "unknown:unknown"); paths != nil {
+		t.Errorf("Paths for unknown dependency %v", paths)
+	}
+}
+
+func TestGraphLoad(t *testing.T) {
+	var buffer bytes.Buffer
+	w, _ := archive.NewWriter(&buffer, true)
+	w.Write(archive.TableStorage, storage.StorageDto{Dependency: "com.google.guava:guava", Version: "32.1.2-jre", Repo: "acme/a", Ref: "main"})
+	w.Write(archive.TableStorage, storage.StorageDto{Dependency: "com.google.guava:guava", Version: "31.0-jre", Repo: "acme/b", Ref: "main", Variant: "freeRelease"})
+	w.Write(archive.TableDependencies, storage.DependencyDto{Parent: "com.google.guava", Child: "guava"})
+	w.Close()
+
+	r, err := archive.NewReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := Load(r)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+
+	items, _ := g.WhoUses("com.google.guava:guava", nil, nil)
+	if len(items) != 2 {
+		t.Errorf("Wrong who-uses %+v", items)
+	}
+	version := "31.0-jre"
+	if items, _ = g.WhoUses("com.google.guava:guava", &version, nil); len(items) != 1 || items[0].Repo != "acme/b" {
+		t.Errorf("Wrong who-uses with version %+v", items)
+	}
+	if items, _ = g.WhoUses("com.google.guava:guava", nil, &storage.StorageFilter{Variant: "paidRelease"}); len(items) != 1 || items[0].Repo != "acme/a" {
+		t.Errorf("Wrong who-uses with filter %+v", items)
+	}
+	if items, _ = g.Dependencies("acme/a", "main", nil); len(items) != 1 {
+		t.Errorf("Wrong deps %+v", items)
+	}
+	if names, _ := g.DependencyNames("com.google.guava"); len(names) != 1 || names[0] != "guava" {
+		t.Errorf("Wrong names %v", names)
+	}
+}
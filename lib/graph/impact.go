@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+)
+
+// Source answers graph queries, implemented by Graph and by storage backed views
+type Source interface {
+	Dependencies(repo string, ref string, filter *storage.StorageFilter) ([]storage.StorageDto, error)
+	WhoUses(dependency string, version *string, filter *storage.StorageFilter) ([]storage.StorageDto, error)
+}
+
+// Impact is how upgrade of dependency to target version affects single repo/ref. Via lists direct dependencies
+// bringing transitive dependency in, Path is the shortest chain from project to dependency
+type Impact struct {
+	Repo      string   `json:"repo"`
+	Ref       string   `json:"ref"`
+	Current   string   `json:"current"`
+	Versions  []string `json:"versions,omitempty"`
+	Jump      string   `json:"jump"`
+	Downgrade bool     `json:"downgrade,omitempty"`
+	Direct    bool     `json:"direct"`
+	Via       []string `json:"via,omitempty"`
+	Path      []string `json:"path,omitempty"`
+}
+
+// BlastRadius lists every repo/ref using dependency, ordered by the size of jump to target version
+func BlastRadius(src Source, dependency string, target string) ([]Impact, error) {
+	usages, err := src.WhoUses(dependency, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string]storage.StorageDto{}
+	for _, usage := range usages {
+		refs[fmt.Sprintf("%s:%s", usage.Repo, usage.Ref)] = usage
+	}
+
+	result := make([]Impact, 0, len(refs))
+	for _, usage := range refs {
+		items, err := src.Dependencies(usage.Repo, usage.Ref, nil)
+		if err != nil {
+			return nil, err
+		}
+		if impact := ImpactOf(items, dependency, target); impact != nil {
+			impact.Repo, impact.Ref = usage.Repo, usage.Ref
+			result = append(result, *impact)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if rank(result[i]) != rank(result[j]) {
+			return rank(result[i]) < rank(result[j])
+		}
+		if result[i].Repo != result[j].Repo {
+			return result[i].Repo < result[j].Repo
+		}
+		return result[i].Ref < result[j].Ref
+	})
+	return result, nil
+}
+
+// ImpactOf describes upgrade of dependency within items of single repo/ref, nil when dependency is not used.
+// Variants could resolve different versions, the oldest one is current as it makes the biggest jump
+func ImpactOf(items []storage.StorageDto, dependency string, target string) *Impact {
+	var impact *Impact
+	for _, item := range items {
+		if item.Dependency != dependency || (item.Kind != "" && item.Kind != storage.KindLibrary && item.Kind != storage.KindPlatform) {
+			continue
+		}
+		if impact == nil {
+			impact = &Impact{Repo: item.Repo, Ref: item.Ref, Current: item.Version}
+		}
+		impact.Versions = append(impact.Versions, item.Version)
+		impact.Direct = impact.Direct || item.Direct
+		if maven.CompareVersions(item.Version, impact.Current) < 0 {
+			impact.Current = item.Version
+		}
+	}
+	if impact == nil {
+		return nil
+	}
+
+	impact.Versions = uniqueSorted(impact.Versions)
+	if len(impact.Versions) == 1 {
+		impact.Versions = nil
+	}
+	impact.Jump = maven.Jump(impact.Current, target)
+	impact.Downgrade = maven.CompareVersions(target, impact.Current) < 0
+
+	paths := Paths(items, dependency)
+	var via []string
+	for _, path := range paths {
+		if len(path) > 1 {
+			via = append(via, path[0])
+		}
+	}
+	impact.Via = uniqueSorted(via)
+	if len(paths) > 0 {
+		impact.Path = paths[0]
+	}
+	return impact
+}
+
+// rank orders impacts from major jumps to unchanged refs
+func rank(impact Impact) int {
+	switch impact.Jump {
+	case maven.JumpMajor:
+		return 0
+	case maven.JumpMinor:
+		return 1
+	case maven.JumpPatch:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"reflect"
+	"testing"
+)
+
+func TestBlastRadius(t *testing.T) {
+	g := NewGraph()
+	for _, item := range []storage.StorageDto{
+		{Repo: "acme/app", Ref: "main", Dependency: "com.acme:core", Version: "1.4.0", Kind: storage.KindLibrary, Direct: true},
+		{Repo: "acme/web", Ref: "main", Dependency: "com.acme:client", Version: "2.0.0", Kind: storage.KindLibrary, Direct: true},
+		{Repo: "acme/web", Ref: "main", Dependency: "com.acme:core", Version: "1.2.3", Kind: storage.KindLibrary, Parents: []string{"com.acme:client"}},
+		{Repo: "acme/web", Ref: "main", Dependency: "com.acme:core", Version: "1.2.0", Kind: storage.KindLibrary, Variant: "legacy", Parents: []string{"com.acme:client"}},
+		{Repo: "acme/batch", Ref: "main", Dependency: "com.acme:core", Version: "2.0.0", Kind: storage.KindLibrary, Direct: true},
+		{Repo: "acme/other", Ref: "main", Dependency: "com.acme:client", Version: "2.0.0", Kind: storage.KindLibrary, Direct: true},
+	} {
+		g.AddItem(item)
+	}
+
+	impacts, err := BlastRadius(g, "com.acme:core", "2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(impacts) != 3 {
+		t.Fatalf("Expected 3 affected refs, got %+v", impacts)
+	}
+
+	app, web, batch := impacts[0], impacts[1], impacts[2]
+	if app.Repo != "acme/app" || app.Jump != maven.JumpMajor || !app.Direct || app.Via != nil {
+		t.Errorf("Unexpected direct impact %+v", app)
+	}
+	if web.Repo != "acme/web" || web.Current != "1.2.0" || web.Direct {
+		t.Errorf("Unexpected transitive impact %+v", web)
+	}
+	if !reflect.DeepEqual(web.Via, []string{"com.acme:client"}) || !reflect.DeepEqual(web.Path, []string{"com.acme:client", "com.acme:core"}) {
+		t.Errorf("Unexpected intermediate dependency %+v", web)
+	}
+	if !reflect.DeepEqual(web.Versions, []string{"1.2.0", "1.2.3"}) {
+		t.Errorf("Expected variant versions, got %v", web.Versions)
+	}
+	if batch.Repo != "acme/batch" || batch.Jump != "" {
+		t.Errorf("Expected unchanged ref last, got %+v", batch)
+	}
+}
+
+func TestImpactDowngrade(t *testing.T) {
+	items := []storage.StorageDto{{Dependency: "com.acme:core", Version: "2.1.0", Direct: true}}
+	impact := ImpactOf(items, "com.acme:core", "2.0.5")
+	if impact == nil || impact.Jump != maven.JumpMinor || !impact.Downgrade {
+		t.Errorf("Expected minor downgrade, got %+v", impact)
+	}
+	if ImpactOf(items, "com.acme:other", "1.0") != nil {
+		t.Error("Expected nil for unused dependency")
+	}
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+)
+
+const maxPaths = 100
+
+// Paths lists dependency chains from project (direct dependency first) to requested dependency,
+// using edges stored with repo/ref dependencies
+func Paths(items []storage.StorageDto, dependency string) [][]string {
+	parents := make(map[string][]string)
+	direct := make(map[string]bool)
+	known := make(map[string]bool)
+	for _, item := range items {
+		known[item.Dependency] = true
+		parents[item.Dependency] = append(parents[item.Dependency], item.Parents...)
+		direct[item.Dependency] = direct[item.Dependency] || item.Direct
+	}
+	if !known[dependency] {
+		return nil
+	}
+
+	var result [][]string
+	visiting := make(map[string]bool)
+
+	var walk func(node string, chain []string)
+	walk = func(node string, chain []string) {
+		if len(result) >= maxPaths || visiting[node] {
+			return
+		}
+		visiting[node] = true
+		defer delete(visiting, node)
+
+		chain = append([]string{node}, chain...)
+		if direct[node] {
+			result = append(result, chain)
+		}
+		for _, parent := range uniqueSorted(parents[node]) {
+			walk(parent, chain)
+		}
+	}
+	walk(dependency, nil)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+
+	return result
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, val := range values {
+		if !seen[val] {
+			seen[val] = true
+			result = append(result, val)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
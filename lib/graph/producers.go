@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+	"strings"
+)
+
+// Producers resolves repository publishing artifact, declared artifacts win over group prefixes
+type Producers struct {
+	artifacts map[string]string
+	prefixes  map[string]string
+}
+
+// RepoEdge is Consumer repository depending on artifacts published by Producer repository
+type RepoEdge struct {
+	Consumer  string   `json:"consumer"`
+	Producer  string   `json:"producer"`
+	Artifacts []string `json:"artifacts"`
+}
+
+// RepoLink is repository reachable from another one, Depth 1 is direct link
+type RepoLink struct {
+	Repo      string   `json:"repo"`
+	Depth     int      `json:"depth"`
+	Via       string   `json:"via,omitempty"`
+	Artifacts []string `json:"artifacts"`
+}
+
+// RepoLinks is cross-repository view of single repository
+type RepoLinks struct {
+	Repo       string     `json:"repo"`
+	Artifacts  []string   `json:"artifacts"`
+	Upstream   []RepoLink `json:"upstream"`
+	Downstream []RepoLink `json:"downstream"`
+}
+
+func NewProducers(declared []storage.ProducerDto, prefixes map[string]string) *Producers {
+	p := &Producers{artifacts: make(map[string]string, len(declared)), prefixes: prefixes}
+	for _, producer := range declared {
+		p.artifacts[producer.Artifact] = producer.Repo
+	}
+	return p
+}
+
+// ParsePrefixes reads comma separated group-prefix=org/repo pairs, e.g. com.acme.payments=acme/payments
+func ParsePrefixes(spec string) (map[string]string, error) {
+	prefixes := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.Count(strings.TrimSpace(parts[1]), "/") != 1 {
+			return nil, fmt.Errorf("malformed producer prefix %q, expected group-prefix=org/repo", pair)
+		}
+		prefixes[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return prefixes, nil
+}
+
+// Repo returns repository publishing dependency group:name, the longest group prefix matching on '.' boundary is used
+// for artifacts not declared by uploads
+func (p *Producers) Repo(dependency string) (string, bool) {
+	if repo, ok := p.artifacts[dependency]; ok {
+		return repo, true
+	}
+	group := strings.SplitN(dependency, ":", 2)[0]
+	var best string
+	for prefix := range p.prefixes {
+		if (group == prefix || strings.HasPrefix(group, prefix+".")) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return p.prefixes[best], true
+}
+
+// RepoGraph links repositories through dependencies on artifacts they publish, refs of repository are merged
+// and repository using own artifacts is not linked with itself
+func RepoGraph(items []storage.StorageDto, producers *Producers) []RepoEdge {
+	artifacts := map[[2]string]map[string]bool{}
+	for _, item := range items {
+		if item.Kind != "" && item.Kind != storage.KindLibrary && item.Kind != storage.KindPlatform {
+			continue
+		}
+		producer, ok := producers.Repo(item.Dependency)
+		if !ok || producer == item.Repo {
+			continue
+		}
+		key := [2]string{item.Repo, producer}
+		if artifacts[key] == nil {
+			artifacts[key] = map[string]bool{}
+		}
+		artifacts[key][item.Dependency+":"+item.Version] = true
+	}
+
+	result := make([]RepoEdge, 0, len(artifacts))
+	for key, set := range artifacts {
+		edge := RepoEdge{Consumer: key[0], Producer: key[1]}
+		for artifact := range set {
+			edge.Artifacts = append(edge.Artifacts, artifact)
+		}
+		sort.Strings(edge.Artifacts)
+		result = append(result, edge)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Consumer != result[j].Consumer {
+			return result[i].Consumer < result[j].Consumer
+		}
+		return result[i].Producer < result[j].Producer
+	})
+	return result
+}
+
+// Upstream lists repositories repo depends on, directly or through other repositories
+func Upstream(edges []RepoEdge, repo string) []RepoLink {
+	return walkRepos(edges, repo, func(edge RepoEdge) (string, string) { return edge.Consumer, edge.Producer })
+}
+
+// Downstream lists repositories depending on repo, directly or through other repositories
+func Downstream(edges []RepoEdge, repo string) []RepoLink {
+	return walkRepos(edges, repo, func(edge RepoEdge) (string, string) { return edge.Producer, edge.Consumer })
+}
+
+// walkRepos is breadth first search, so every repository is reported at its shortest depth
+func walkRepos(edges []RepoEdge, repo string, direction func(edge RepoEdge) (string, string)) []RepoLink {
+	next := map[string][]RepoEdge{}
+	for _, edge := range edges {
+		from, _ := direction(edge)
+		next[from] = append(next[from], edge)
+	}
+
+	var result []RepoLink
+	visited := map[string]bool{repo: true}
+	queue := []RepoLink{{Repo: repo}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range next[current.Repo] {
+			_, to := direction(edge)
+			if visited[to] {
+				continue
+			}
+			visited[to] = true
+			link := RepoLink{Repo: to, Depth: current.Depth + 1, Artifacts: edge.Artifacts}
+			if current.Depth > 0 {
+				link.Via = current.Repo
+			}
+			result = append(result, link)
+			queue = append(queue, link)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Depth != result[j].Depth {
+			return result[i].Depth < result[j].Depth
+		}
+		return result[i].Repo < result[j].Repo
+	})
+	return result
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"reflect"
+	"testing"
+)
+
+func TestProducersRepo(t *testing.T) {
+	prefixes, err := ParsePrefixes("com.acme=acme/platform, com.acme.payments=acme/payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	producers := NewProducers([]storage.ProducerDto{{Artifact: "com.acme.payments:legacy-client", Repo: "acme/legacy"}}, prefixes)
+
+	cases := map[string]string{
+		"com.acme.payments:client":        "acme/payments",
+		"com.acme.payments.api:model":     "acme/payments",
+		"com.acme:core":                   "acme/platform",
+		"com.acme.payments:legacy-client": "acme/legacy",
+		"com.acmecorp:core":               "",
+		"org.slf4j:slf4j-api":             "",
+	}
+	for dependency, expected := range cases {
+		if repo, _ := producers.Repo(dependency); repo != expected {
+			t.Errorf("Repo(%s) = %q, expected %q", dependency, repo, expected)
+		}
+	}
+
+	if _, err := ParsePrefixes("com.acme"); err == nil {
+		t.Error("Expected error for prefix without repository")
+	}
+}
+
+func TestRepoGraph(t *testing.T) {
+	producers := NewProducers([]storage.ProducerDto{
+		{Artifact: "com.acme:core", Repo: "acme/core"},
+		{Artifact: "com.acme:client", Repo: "acme/client"},
+	}, nil)
+	items := []storage.StorageDto{
+		{Repo: "acme/client", Ref: "main", Dependency: "com.acme:core", Version: "1.0"},
+		{Repo: "acme/app", Ref: "main", Dependency: "com.acme:client", Version: "2.0"},
+		{Repo: "acme/app", Ref: "main", Dependency: "com.acme:core", Version: "1.0"},
+		{Repo: "acme/app", Ref: "feature", Dependency: "com.acme:core", Version: "1.1"},
+		{Repo: "acme/core", Ref: "main", Dependency: "com.acme:core", Version: "1.0"},
+		{Repo: "acme/app", Ref: "main", Dependency: "org.slf4j:slf4j-api", Version: "2.0.9"},
+	}
+
+	edges := RepoGraph(items, producers)
+	expected := []RepoEdge{
+		{Consumer: "acme/app", Producer: "acme/client", Artifacts: []string{"com.acme:client:2.0"}},
+		{Consumer: "acme/app", Producer: "acme/core", Artifacts: []string{"com.acme:core:1.0", "com.acme:core:1.1"}},
+		{Consumer: "acme/client", Producer: "acme/core", Artifacts: []string{"com.acme:core:1.0"}},
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Fatalf("Unexpected edges %+v", edges)
+	}
+
+	downstream := Downstream(edges, "acme/core")
+	if len(downstream) != 2 || downstream[0].Repo != "acme/app" || downstream[0].Depth != 1 || downstream[1].Repo != "acme/client" {
+		t.Errorf("Unexpected downstream %+v", downstream)
+	}
+	upstream := Upstream(edges, "acme/app")
+	if len(upstream) != 2 || upstream[0].Repo != "acme/client" || upstream[1].Repo != "acme/core" || upstream[1].Depth != 1 {
+		t.Errorf("Unexpected upstream %+v", upstream)
+	}
+	if upstream := Upstream(edges, "acme/core"); upstream != nil {
+		t.Errorf("Expected no upstream of core, got %+v", upstream)
+	}
+}
+
+func TestDownstreamTransitive(t *testing.T) {
+	edges := []RepoEdge{
+		{Consumer: "acme/app", Producer: "acme/client"},
+		{Consumer: "acme/client", Producer: "acme/core"},
+	}
+	downstream := Downstream(edges, "acme/core")
+	expected := []RepoLink{{Repo: "acme/client", Depth: 1}, {Repo: "acme/app", Depth: 2, Via: "acme/client"}}
+	if !reflect.DeepEqual(downstream, expected) {
+		t.Errorf("Unexpected downstream %+v", downstream)
+	}
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -58,6 +59,12 @@ func ApiErrorUnknown() *events.APIGatewayProxyResponse {
 	return &resp
 }
 
+func ApiError(statusCode int, status string) *events.APIGatewayProxyResponse {
+	return ApiResponse(statusCode, &ResponseNotFound{
+		Status: &status,
+	})
+}
+
 func ApiErrorNoContent() *events.APIGatewayProxyResponse {
 	resp := events.APIGatewayProxyResponse{
 		Headers: map[string]string{"Content-Type": "application/json"},
@@ -156,6 +163,11 @@ func GenerateMD5(text string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+func GenerateSHA256(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
 func GenerateMD5List(texts []string) []string {
 	result := make([]string, len(texts))
 	for idx, val := range texts {
@@ -163,3 +175,77 @@ func GenerateMD5List(texts []string) []string {
 	}
 	return result
 }
+
+// GradleVersionFromDistributionUrl extracts version from gradle-wrapper.properties distributionUrl,
+// e.g. https\://services.gradle.org/distributions/gradle-8.4-bin.zip -> 8.4
+func GradleVersionFromDistributionUrl(url string) string {
+	name := url[strings.LastIndex(url, "/")+1:]
+	if !strings.HasPrefix(name, "gradle-") || !strings.HasSuffix(name, ".zip") {
+		return ""
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "gradle-"), ".zip")
+	for _, suffix := range []string{"-bin", "-all", "-src"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+// AndroidVariantName composes variant name the same way Android Gradle Plugin does,
+// e.g. flavors [free, arm64] and build type release -> freeArm64Release
+func AndroidVariantName(buildType string, flavors []string) string {
+	var parts []string
+	for _, part := range append(append([]string{}, flavors...), buildType) {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	var name strings.Builder
+	for idx, part := range parts {
+		if idx == 0 {
+			name.WriteString(strings.ToLower(part[:1]) + part[1:])
+		} else {
+			name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return name.String()
+}
+
+func Unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, val := range values {
+		if !seen[val] {
+			seen[val] = true
+			result = append(result, val)
+		}
+	}
+	return result
+}
+
+// WantsJson reports whether client asked for JSON instead of HTML page, via ?format=json or Accept header
+func WantsJson(request events.APIGatewayProxyRequest) bool {
+	if request.QueryStringParameters["format"] == "json" {
+		return true
+	}
+	for name, value := range request.Headers {
+		if strings.EqualFold(name, "accept") && strings.Contains(value, "application/json") {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizerPrincipal returns principal set by authorizer lambda into request context
+func AuthorizerPrincipal(request events.APIGatewayV2HTTPRequest) string {
+	return AuthorizerContext(request, "principal")
+}
+
+// AuthorizerContext returns string value set by authorizer lambda into request context
+func AuthorizerContext(request events.APIGatewayV2HTTPRequest, key string) string {
+	if request.RequestContext.Authorizer == nil {
+		return ""
+	}
+	value, _ := request.RequestContext.Authorizer.Lambda[key].(string)
+	return value
+}
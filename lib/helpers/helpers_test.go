@@ -13,3 +13,40 @@ func TestInitLogger(t *testing.T) {
 
 	logger.Debug("Test")
 }
+
+func TestGradleVersionFromDistributionUrl(t *testing.T) {
+	cases := map[string]string{
+		"https\\://services.gradle.org/distributions/gradle-8.4-bin.zip":       "8.4",
+		"https://services.gradle.org/distributions/gradle-7.6.1-all.zip":       "7.6.1",
+		"https://services.gradle.org/distributions/gradle-8.5-rc-1-bin.zip":    "8.5-rc-1",
+		"https://repo.example.com/gradle/distributions/gradle-6.9.4.zip":       "6.9.4",
+		"https://services.gradle.org/distributions/something-else-1.0-bin.zip": "",
+		"": "",
+	}
+
+	for url, expected := range cases {
+		if version := GradleVersionFromDistributionUrl(url); version != expected {
+			t.Errorf("Wrong version for %s: %s, expected %s", url, version, expected)
+		}
+	}
+}
+
+func TestAndroidVariantName(t *testing.T) {
+	cases := []struct {
+		buildType string
+		flavors   []string
+		expected  string
+	}{
+		{"release", []string{"free"}, "freeRelease"},
+		{"debug", []string{"paid", "arm64"}, "paidArm64Debug"},
+		{"release", nil, "release"},
+		{"", []string{"free"}, "free"},
+		{"", nil, ""},
+	}
+
+	for _, c := range cases {
+		if name := AndroidVariantName(c.buildType, c.flavors); name != c.expected {
+			t.Errorf("Wrong variant name for %s %v: %s, expected %s", c.buildType, c.flavors, name, c.expected)
+		}
+	}
+}
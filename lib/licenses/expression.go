@@ -0,0 +1,135 @@
+package licenses
+
+import (
+	"regexp"
+	"strings"
+)
+
+// idRegexp is SPDX idstring, e.g. Apache-2.0, GPL-2.0+ or LicenseRef-acme
+var idRegexp = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// expression is node of parsed SPDX expression, Op is empty for license id
+type expression struct {
+	Op    string
+	Id    string
+	Nodes []expression
+}
+
+// parseExpression parses SPDX expression with case-sensitive AND, OR and WITH operators, ok is false for
+// free-text license names like "GNU Lesser General Public License v2.1 or later"
+func parseExpression(value string) (expression, bool) {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(value))
+	p := &expressionParser{tokens: tokens}
+	node, ok := p.or()
+	return node, ok && p.pos == len(tokens)
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *expressionParser) or() (expression, bool) {
+	return p.binary("OR", p.and)
+}
+
+func (p *expressionParser) and() (expression, bool) {
+	return p.binary("AND", p.operand)
+}
+
+func (p *expressionParser) binary(op string, next func() (expression, bool)) (expression, bool) {
+	node, ok := next()
+	if !ok {
+		return node, false
+	}
+	nodes := []expression{node}
+	for p.peek() == op {
+		p.pos++
+		if node, ok = next(); !ok {
+			return node, false
+		}
+		if node.Op == op {
+			nodes = append(nodes, node.Nodes...)
+		} else {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 1 {
+		return nodes[0], true
+	}
+	return expression{Op: op, Nodes: nodes}, true
+}
+
+func (p *expressionParser) operand() (expression, bool) {
+	token := p.peek()
+	p.pos++
+	if token == "(" {
+		node, ok := p.or()
+		if !ok || p.peek() != ")" {
+			return node, false
+		}
+		p.pos++
+		return node, true
+	}
+	if token == "AND" || token == "OR" || token == "WITH" || !idRegexp.MatchString(token) {
+		return expression{}, false
+	}
+	id := Normalize(token)
+	if p.peek() == "WITH" {
+		p.pos++
+		exception := p.peek()
+		p.pos++
+		if !idRegexp.MatchString(exception) {
+			return expression{}, false
+		}
+		id += " WITH " + exception
+	}
+	return expression{Id: id}, true
+}
+
+func (e expression) String() string {
+	if e.Op == "" {
+		return e.Id
+	}
+	parts := make([]string, 0, len(e.Nodes))
+	for _, node := range e.Nodes {
+		if node.Op != "" {
+			parts = append(parts, "("+node.String()+")")
+		} else {
+			parts = append(parts, node.String())
+		}
+	}
+	return strings.Join(parts, " "+e.Op+" ")
+}
+
+// alternatives expands expression into choices of licenses applying together
+func (e expression) alternatives() [][]string {
+	switch e.Op {
+	case "OR":
+		var result [][]string
+		for _, node := range e.Nodes {
+			result = append(result, node.alternatives()...)
+		}
+		return result
+	case "AND":
+		result := [][]string{{}}
+		for _, node := range e.Nodes {
+			var combined [][]string
+			for _, prefix := range result {
+				for _, choice := range node.alternatives() {
+					combined = append(combined, append(append([]string{}, prefix...), choice...))
+				}
+			}
+			result = combined
+		}
+		return result
+	}
+	return [][]string{{e.Id}}
+}
@@ -0,0 +1,323 @@
+package licenses
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Unknown is reported for dependencies without license metadata
+const Unknown = "UNKNOWN"
+
+const (
+	CategoryStrongCopyleft = "strong-copyleft"
+	CategoryWeakCopyleft   = "weak-copyleft"
+	CategoryPermissive     = "permissive"
+	CategoryUnknown        = "unknown"
+	CategoryOther          = "other"
+)
+
+// aliases maps license names used in Maven POMs to SPDX ids, keys are lower case without punctuation
+var aliases = map[string]string{
+	"apache 20":                              "Apache-2.0",
+	"apache license 20":                      "Apache-2.0",
+	"apache license version 20":              "Apache-2.0",
+	"the apache license version 20":          "Apache-2.0",
+	"the apache software license version 20": "Apache-2.0",
+	"apache software license version 20":     "Apache-2.0",
+	"asl 20":                                 "Apache-2.0",
+	"mit":                                    "MIT",
+	"mit license":                            "MIT",
+	"the mit license":                        "MIT",
+	"bsd":                                    "BSD-3-Clause",
+	"bsd license":                            "BSD-3-Clause",
+	"new bsd license":                        "BSD-3-Clause",
+	"the bsd license":                        "BSD-3-Clause",
+	"bsd 3 clause":                           "BSD-3-Clause",
+	"bsd 2 clause":                           "BSD-2-Clause",
+	"eclipse public license 10":              "EPL-1.0",
+	"eclipse public license v 10":            "EPL-1.0",
+	"eclipse public license 20":              "EPL-2.0",
+	"eclipse public license v 20":            "EPL-2.0",
+	"eclipse distribution license 10":        "BSD-3-Clause",
+	"mozilla public license 20":              "MPL-2.0",
+	"mpl 20":                                 "MPL-2.0",
+	"gnu lesser general public license":      "LGPL-2.1-or-later",
+	"lgpl 21":                                "LGPL-2.1-only",
+	"gnu general public license v2 with classpath exception": "GPL-2.0-with-classpath-exception",
+	"gpl2 w cpe":            "GPL-2.0-with-classpath-exception",
+	"cddl 10":               "CDDL-1.0",
+	"cddl 11":               "CDDL-1.1",
+	"public domain":         "Unlicense",
+	"the unlicense":         "Unlicense",
+	"cc0":                   "CC0-1.0",
+	"bouncy castle licence": "MIT",
+}
+
+var (
+	punctuationRegexp = regexp.MustCompile(`[^a-z0-9 ]+`)
+	spacesRegexp      = regexp.MustCompile(`\s+`)
+	versionRegexp     = regexp.MustCompile(`-[0-9].*$`)
+)
+
+// Normalize returns SPDX id for license name, unknown names are kept as is
+func Normalize(name string) string {
+	name = strings.TrimSpace(name)
+	key := strings.ToLower(name)
+	key = punctuationRegexp.ReplaceAllString(strings.ReplaceAll(key, "-", " "), "")
+	key = strings.TrimSpace(spacesRegexp.ReplaceAllString(key, " "))
+	if id, ok := aliases[key]; ok {
+		return id
+	}
+	return name
+}
+
+// Split returns licenses of SPDX expression applying together, choices are kept as OR expression, e.g.
+// "(MIT OR Apache-2.0) AND BSD-3-Clause" is [MIT OR Apache-2.0, BSD-3-Clause]. Free-text names are not split
+func Split(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	expr, ok := parseExpression(value)
+	if !ok {
+		return []string{Normalize(value)}
+	}
+	if expr.Op != "AND" {
+		return []string{expr.String()}
+	}
+	result := make([]string, 0, len(expr.Nodes))
+	for _, node := range expr.Nodes {
+		result = append(result, node.String())
+	}
+	return result
+}
+
+// Alternatives returns choices of license expression, each lists licenses applying together, e.g.
+// "MIT OR (Apache-2.0 AND BSD-3-Clause)" is [[MIT] [Apache-2.0 BSD-3-Clause]]
+func Alternatives(license string) [][]string {
+	if expr, ok := parseExpression(license); ok {
+		return expr.alternatives()
+	}
+	return [][]string{{license}}
+}
+
+// Allowed reports whether license expression has alternative made only of licenses matching patterns
+func Allowed(patterns []string, license string) bool {
+	for _, alternative := range Alternatives(license) {
+		allowed := true
+		for _, id := range alternative {
+			allowed = allowed && matchesAny(patterns, id)
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Denied reports whether every alternative of license expression has license matching patterns,
+// so dual-licensed "MPL-2.0 OR GPL-2.0-only" is not denied by GPL
+func Denied(patterns []string, license string) bool {
+	for _, alternative := range Alternatives(license) {
+		denied := false
+		for _, id := range alternative {
+			denied = denied || matchesAny(patterns, id)
+		}
+		if !denied {
+			return false
+		}
+	}
+	return true
+}
+
+// Family strips version and suffixes, e.g. GPL-3.0-or-later is GPL
+func Family(id string) string {
+	id = strings.SplitN(id, " WITH ", 2)[0]
+	return strings.ToUpper(versionRegexp.ReplaceAllString(id, ""))
+}
+
+// categoryRank orders categories from the least restrictive
+var categoryRank = map[string]int{
+	CategoryPermissive:     0,
+	CategoryWeakCopyleft:   1,
+	CategoryOther:          2,
+	CategoryStrongCopyleft: 3,
+	CategoryUnknown:        4,
+}
+
+// Category of license expression is the least restrictive alternative, alternative is as restrictive as its
+// most restrictive license
+func Category(license string) string {
+	result := ""
+	for _, alternative := range Alternatives(license) {
+		category := ""
+		for _, id := range alternative {
+			if c := idCategory(id); category == "" || categoryRank[c] > categoryRank[category] {
+				category = c
+			}
+		}
+		if result == "" || categoryRank[category] < categoryRank[result] {
+			result = category
+		}
+	}
+	return result
+}
+
+func idCategory(id string) string {
+	switch family := Family(id); {
+	case id == Unknown:
+		return CategoryUnknown
+	case family == "GPL" || family == "AGPL" || family == "SSPL" || family == "OSL":
+		if strings.Contains(strings.ToLower(id), "classpath-exception") {
+			return CategoryWeakCopyleft
+		}
+		return CategoryStrongCopyleft
+	case family == "LGPL" || family == "MPL" || family == "EPL" || family == "CDDL" || family == "CPL":
+		return CategoryWeakCopyleft
+	case family == "APACHE" || family == "MIT" || family == "BSD" || family == "ISC" ||
+		family == "UNLICENSE" || family == "CC0" || family == "0BSD" || family == "ZLIB":
+		return CategoryPermissive
+	}
+	return CategoryOther
+}
+
+// Matches compares license id with exact id, family (GPL does not match LGPL or AGPL) or glob
+func Matches(pattern string, id string) bool {
+	if strings.EqualFold(pattern, id) || strings.EqualFold(pattern, Family(id)) {
+		return true
+	}
+	matched, _ := path.Match(pattern, id)
+	return matched
+}
+
+// Of returns licenses of item or Unknown
+func Of(licenses []string) []string {
+	if len(licenses) == 0 {
+		return []string{Unknown}
+	}
+	return licenses
+}
+
+// NormalizeDependencies replaces license names and expressions of payload with SPDX ids
+func NormalizeDependencies(deps *storage.DependenciesRest) {
+	for idx, dep := range deps.Dependencies {
+		var normalized []string
+		for _, license := range dep.Licenses {
+			normalized = append(normalized, Split(license)...)
+		}
+		deps.Dependencies[idx].Licenses = normalized
+	}
+}
+
+// Resolve fills licenses missing in payload from mapping items keyed by group:name:version or group:name:*
+func Resolve(deps *storage.DependenciesRest, mapped map[string]storage.LicenseDto) {
+	for idx, dep := range deps.Dependencies {
+		if len(dep.Licenses) > 0 {
+			continue
+		}
+		dependency := fmt.Sprintf("%s:%s", dep.Group, dep.Name)
+		for _, version := range []string{dep.Version, storage.LicenseAnyVersion} {
+			if item, ok := mapped[storage.VersionKey(dependency, version)]; ok {
+				deps.Dependencies[idx].Licenses = item.Licenses
+				break
+			}
+		}
+	}
+}
+
+// Unresolved returns mapping keys to look up for dependencies without licenses in payload
+func Unresolved(deps storage.DependenciesRest) []storage.LicenseDto {
+	seen := map[string]bool{}
+	var result []storage.LicenseDto
+	for _, dep := range deps.Dependencies {
+		if len(dep.Licenses) > 0 {
+			continue
+		}
+		dependency := fmt.Sprintf("%s:%s", dep.Group, dep.Name)
+		for _, version := range []string{dep.Version, storage.LicenseAnyVersion} {
+			if key := storage.VersionKey(dependency, version); !seen[key] {
+				seen[key] = true
+				result = append(result, storage.LicenseDto{Dependency: dependency, Version: version})
+			}
+		}
+	}
+	return result
+}
+
+// Usage groups stored libraries by license
+type Usage struct {
+	License      string               `json:"license"`
+	Category     string               `json:"category"`
+	Repos        []string             `json:"repos"`
+	Dependencies []storage.StorageDto `json:"dependencies"`
+}
+
+// Inventory groups libraries by license, items matching none of patterns are skipped when patterns are given
+func Inventory(items []storage.StorageDto, patterns ...string) []Usage {
+	index := map[string]*Usage{}
+	repos := map[string]map[string]bool{}
+	for _, item := range items {
+		if item.Kind != storage.KindLibrary && item.Kind != storage.KindPlatform {
+			continue
+		}
+		for _, license := range Of(item.Licenses) {
+			if len(patterns) > 0 && !mentions(patterns, license) {
+				continue
+			}
+			usage, ok := index[license]
+			if !ok {
+				usage = &Usage{License: license, Category: Category(license)}
+				index[license] = usage
+				repos[license] = map[string]bool{}
+			}
+			usage.Dependencies = append(usage.Dependencies, item)
+			repo := fmt.Sprintf("%s/%s", item.Repo, item.Ref)
+			if !repos[license][repo] {
+				repos[license][repo] = true
+				usage.Repos = append(usage.Repos, repo)
+			}
+		}
+	}
+
+	result := make([]Usage, 0, len(index))
+	for _, usage := range index {
+		sort.Strings(usage.Repos)
+		sort.SliceStable(usage.Dependencies, func(i, j int) bool {
+			a, b := usage.Dependencies[i], usage.Dependencies[j]
+			if a.Repo+a.Ref != b.Repo+b.Ref {
+				return a.Repo+"/"+a.Ref < b.Repo+"/"+b.Ref
+			}
+			return a.Dependency < b.Dependency
+		})
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].License < result[j].License
+	})
+	return result
+}
+
+// mentions reports whether any license of expression matches patterns
+func mentions(patterns []string, license string) bool {
+	for _, alternative := range Alternatives(license) {
+		for _, id := range alternative {
+			if matchesAny(patterns, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, license string) bool {
+	for _, pattern := range patterns {
+		if Matches(pattern, license) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package licenses
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"The Apache Software License, Version 2.0": "Apache-2.0",
+		"Apache License, Version 2.0":              "Apache-2.0",
+		"Apache-2.0":                               "Apache-2.0",
+		"The MIT License":                          "MIT",
+		"BSD 3-Clause":                             "BSD-3-Clause",
+		"Eclipse Public License - v 1.0":           "EPL-1.0",
+		"GPL2 w/ CPE":                              "GPL-2.0-with-classpath-exception",
+		" EPL-2.0 ":                                "EPL-2.0",
+		"Some Vendor License":                      "Some Vendor License",
+	}
+	for name, expected := range cases {
+		if actual := Normalize(name); actual != expected {
+			t.Errorf("Normalize(%q) = %q, expected %q", name, actual, expected)
+		}
+	}
+
+	splits := map[string][]string{
+		"(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0": {"MIT OR Apache-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		"(MPL-2.0 OR EPL-1.0)":                            {"MPL-2.0 OR EPL-1.0"},
+		"MIT OR (Apache-2.0 AND BSD-3-Clause)":            {"MIT OR (Apache-2.0 AND BSD-3-Clause)"},
+		"GNU Lesser General Public License v2.1 or later": {"GNU Lesser General Public License v2.1 or later"},
+		"Apache License, Version 2.0":                     {"Apache-2.0"},
+		"MIT or Apache-2.0":                               {"MIT or Apache-2.0"},
+		"":                                                nil,
+	}
+	for value, expected := range splits {
+		if actual := Split(value); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Split(%q) = %q, expected %q", value, actual, expected)
+		}
+	}
+
+	if actual := Alternatives("MIT OR (Apache-2.0 AND BSD-3-Clause)"); !reflect.DeepEqual(actual, [][]string{{"MIT"}, {"Apache-2.0", "BSD-3-Clause"}}) {
+		t.Errorf("Wrong alternatives %v", actual)
+	}
+}
+
+func TestAllowedAndDenied(t *testing.T) {
+	if Denied([]string{"GPL"}, "MPL-2.0 OR GPL-2.0-only") || !Denied([]string{"GPL"}, "GPL-2.0-only OR GPL-3.0-only") {
+		t.Error("Expression is denied only when every alternative is denied")
+	}
+	if Denied([]string{"GPL"}, "MIT OR (Apache-2.0 AND GPL-3.0-only)") || !Denied([]string{"GPL", "MIT"}, "MIT OR (Apache-2.0 AND GPL-3.0-only)") {
+		t.Error("Alternative is denied by any of its licenses")
+	}
+	if !Allowed([]string{"MIT"}, "MIT OR GPL-3.0-only") || Allowed([]string{"Apache-2.0"}, "MIT OR (Apache-2.0 AND GPL-3.0-only)") {
+		t.Error("Expression is allowed when all licenses of some alternative are allowed")
+	}
+}
+
+func TestCategoryAndMatches(t *testing.T) {
+	cases := map[string]string{
+		"GPL-3.0-or-later": CategoryStrongCopyleft,
+		"AGPL-3.0-only":    CategoryStrongCopyleft,
+		"GPL-2.0-only WITH Classpath-exception-2.0": CategoryWeakCopyleft,
+		"LGPL-2.1-only":                     CategoryWeakCopyleft,
+		"EPL-2.0":                           CategoryWeakCopyleft,
+		"Apache-2.0":                        CategoryPermissive,
+		"BSD-3-Clause":                      CategoryPermissive,
+		Unknown:                             CategoryUnknown,
+		"Some Vendor License":               CategoryOther,
+		"GPL-2.0-only OR MIT":               CategoryPermissive,
+		"EPL-2.0 OR (MIT AND GPL-3.0-only)": CategoryWeakCopyleft,
+	}
+	for id, expected := range cases {
+		if actual := Category(id); actual != expected {
+			t.Errorf("Category(%q) = %q, expected %q", id, actual, expected)
+		}
+	}
+
+	if !Matches("GPL", "GPL-3.0-only") || Matches("GPL", "LGPL-2.1-only") || Matches("GPL", "AGPL-3.0-only") {
+		t.Error("GPL family should match only GPL licenses")
+	}
+	if !Matches("agpl", "AGPL-3.0-only") || !Matches("EPL-*", "EPL-2.0") || !Matches(Unknown, Unknown) {
+		t.Error("Expected family, glob and unknown matches")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	deps := storage.DependenciesRest{
+		Dependencies: []storage.DependencyRest{
+			{Group: "org.hibernate", Name: "hibernate-core", Version: "5.6.15.Final"},
+			{Group: "com.h2database", Name: "h2", Version: "2.1.214"},
+			{Group: "acme", Name: "core", Version: "1.0", Licenses: []string{"The MIT License"}},
+			{Group: "acme", Name: "unmapped", Version: "1.0"},
+		},
+	}
+	NormalizeDependencies(&deps)
+
+	keys := Unresolved(deps)
+	if len(keys) != 6 || keys[0].Version != "5.6.15.Final" || keys[1].Version != storage.LicenseAnyVersion {
+		t.Fatalf("Wrong unresolved keys %+v", keys)
+	}
+
+	mapping, err := ParseMapping([]byte(`
+org.hibernate:hibernate-core: LGPL-2.1-only
+org.hibernate:hibernate-core:5.6.15.Final: [LGPL-2.1-or-later]
+com.h2database:h2: MPL-2.0 OR EPL-1.0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapped := map[string]storage.LicenseDto{}
+	for _, item := range mapping {
+		mapped[storage.VersionKey(item.Dependency, item.Version)] = item
+	}
+	Resolve(&deps, mapped)
+
+	expected := [][]string{{"LGPL-2.1-or-later"}, {"MPL-2.0 OR EPL-1.0"}, {"MIT"}, nil}
+	for idx, dep := range deps.Dependencies {
+		if !reflect.DeepEqual(dep.Licenses, expected[idx]) {
+			t.Errorf("Wrong licenses of %s: %v", dep.Name, dep.Licenses)
+		}
+	}
+
+	if _, err := ParseMapping([]byte(`hibernate-core: MIT`)); err == nil {
+		t.Error("Expected error for key without group")
+	}
+}
+
+func TestInventory(t *testing.T) {
+	items := []storage.StorageDto{
+		{Repo: "acme/app", Ref: "main", Dependency: "org.hibernate:hibernate-core", Version: "5.6.15.Final", Kind: storage.KindLibrary, Licenses: []string{"LGPL-2.1-only"}},
+		{Repo: "acme/app", Ref: "main", Dependency: "acme:gpl-lib", Version: "1.0", Kind: storage.KindLibrary, Licenses: []string{"GPL-3.0-only"}},
+		{Repo: "acme/web", Ref: "main", Dependency: "acme:gpl-lib", Version: "1.1", Kind: storage.KindLibrary, Licenses: []string{"GPL-3.0-only"}},
+		{Repo: "acme/web", Ref: "main", Dependency: "acme:unknown", Version: "1.0", Kind: storage.KindLibrary},
+		{Repo: "acme/web", Ref: "main", Dependency: "org.jetbrains.kotlin.jvm", Version: "1.8.0", Kind: storage.KindPlugin},
+	}
+
+	usages := Inventory(items)
+	if len(usages) != 3 {
+		t.Fatalf("Wrong usages %+v", usages)
+	}
+	if usages[0].License != "GPL-3.0-only" || !reflect.DeepEqual(usages[0].Repos, []string{"acme/app/main", "acme/web/main"}) {
+		t.Errorf("Wrong GPL usage %+v", usages[0])
+	}
+	if usages[2].License != Unknown || len(usages[2].Dependencies) != 1 {
+		t.Errorf("Wrong unknown usage %+v", usages[2])
+	}
+
+	gpl := Inventory(items, "GPL")
+	if len(gpl) != 1 || len(gpl[0].Dependencies) != 2 {
+		t.Errorf("Wrong GPL inventory %+v", gpl)
+	}
+}
@@ -0,0 +1,66 @@
+package licenses
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+	"sort"
+	"strings"
+)
+
+const SourceMapping = "mapping"
+
+// LoadMapping reads YAML or JSON file mapping group:name or group:name:version to SPDX expression or list of licenses:
+//
+//	org.apache.commons:commons-lang3: Apache-2.0
+//	com.h2database:h2: MPL-2.0 OR EPL-1.0
+//	org.hibernate:hibernate-core:5.6.15.Final: [LGPL-2.1-only]
+func LoadMapping(file string) ([]storage.LicenseDto, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMapping(data)
+}
+
+func ParseMapping(data []byte) ([]storage.LicenseDto, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("malformed license mapping: %s", err)
+	}
+
+	result := make([]storage.LicenseDto, 0, len(raw))
+	for key, value := range raw {
+		parts := strings.Split(key, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%s: expected group:name or group:name:version", key)
+		}
+		item := storage.LicenseDto{
+			Dependency: parts[0] + ":" + parts[1],
+			Version:    storage.LicenseAnyVersion,
+			Source:     SourceMapping,
+		}
+		if len(parts) == 3 {
+			item.Version = parts[2]
+		}
+
+		switch v := value.(type) {
+		case string:
+			item.Licenses = Split(v)
+		case []interface{}:
+			for _, license := range v {
+				item.Licenses = append(item.Licenses, Split(fmt.Sprint(license))...)
+			}
+		}
+		if len(item.Licenses) == 0 {
+			return nil, fmt.Errorf("%s: expected license or list of licenses", key)
+		}
+		result = append(result, item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return storage.VersionKey(result[i].Dependency, result[i].Version) < storage.VersionKey(result[j].Dependency, result[j].Version)
+	})
+	return result, nil
+}
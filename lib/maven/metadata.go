@@ -0,0 +1,151 @@
+package maven
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Mirror when artifact or version is missing in repository
+var ErrNotFound = errors.New("not found in maven repository")
+
+// Metadata is maven-metadata.xml of artifact, e.g. https://repo1.maven.org/maven2/com/google/guava/guava/maven-metadata.xml
+type Metadata struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Versioning struct {
+		Latest      string   `xml:"latest"`
+		Release     string   `xml:"release"`
+		Versions    []string `xml:"versions>version"`
+		LastUpdated string   `xml:"lastUpdated"`
+	} `xml:"versioning"`
+}
+
+func ParseMetadata(reader io.Reader) (*Metadata, error) {
+	var metadata Metadata
+	if err := xml.NewDecoder(reader).Decode(&metadata); err != nil {
+		return nil, errors.Wrap(err, "malformed maven-metadata.xml")
+	}
+	return &metadata, nil
+}
+
+// LatestRelease returns newest version which is not pre-release, falling back to <release>
+func LatestRelease(versions []string, release string) string {
+	var latest string
+	for _, version := range versions {
+		if IsPreRelease(version) {
+			continue
+		}
+		if latest == "" || CompareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	if latest == "" {
+		return release
+	}
+	return latest
+}
+
+// Mirror reads Maven repository layout from file:// directory or http(s):// server
+type Mirror struct {
+	Url  *url.URL
+	Http *http.Client
+}
+
+func NewMirror(repositoryUrl string) (*Mirror, error) {
+	parsed, err := url.Parse(strings.TrimRight(repositoryUrl, "/"))
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed maven repository url")
+	}
+	if parsed.Scheme != "file" && parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("maven repository url must be file://, http:// or https://, got %q", repositoryUrl)
+	}
+	return &Mirror{Url: parsed, Http: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (m *Mirror) Metadata(ctx context.Context, group string, name string) (*Metadata, error) {
+	path, err := artifactPath(group, name)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := m.open(ctx, http.MethodGet, path+"/maven-metadata.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ParseMetadata(body)
+}
+
+// Published returns time version was deployed: pom modification time of file mirror or Last-Modified header
+func (m *Mirror) Published(ctx context.Context, group string, name string, version string) (time.Time, error) {
+	path, err := artifactPath(group, name, version)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	body, modified, err := m.open(ctx, http.MethodHead, fmt.Sprintf("%s/%s-%s.pom", path, name, version))
+	if err != nil {
+		return time.Time{}, err
+	}
+	body.Close()
+	if modified.IsZero() {
+		return time.Time{}, fmt.Errorf("%s:%s:%s: unknown publication time", group, name, version)
+	}
+	return modified.UTC(), nil
+}
+
+func (m *Mirror) open(ctx context.Context, method string, path string) (io.ReadCloser, time.Time, error) {
+	if m.Url.Scheme == "file" {
+		file, err := os.Open(filepath.Join(m.Url.Path, filepath.FromSlash(path)))
+		if os.IsNotExist(err) {
+			return nil, time.Time{}, ErrNotFound
+		}
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, time.Time{}, err
+		}
+		return file, info.ModTime(), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.Url.String()+"/"+path, nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resp, err := m.Http.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, time.Time{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, time.Time{}, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	modified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return resp.Body, modified, nil
+}
+
+// artifactPath converts coordinates to repository layout, refusing parts which could leave repository root
+func artifactPath(group string, parts ...string) (string, error) {
+	for _, part := range append([]string{group}, parts...) {
+		if part == "" || strings.ContainsAny(part, "/\\") || strings.Contains(part, "..") {
+			return "", fmt.Errorf("malformed coordinate %q", part)
+		}
+	}
+	return strings.Join(append([]string{strings.ReplaceAll(group, ".", "/")}, parts...), "/"), nil
+}
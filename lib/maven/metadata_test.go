@@ -0,0 +1,157 @@
+package maven
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const guavaMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>com.google.guava</groupId>
+  <artifactId>guava</artifactId>
+  <versioning>
+    <latest>33.0.0-jre</latest>
+    <release>33.0.0-jre</release>
+    <versions>
+      <version>31.1-jre</version>
+      <version>32.0.0-jre</version>
+      <version>32.1.0-jre</version>
+      <version>33.0.0-jre</version>
+      <version>34.0.0-rc1</version>
+    </versions>
+    <lastUpdated>20231218150542</lastUpdated>
+  </versioning>
+</metadata>`
+
+func TestParseMetadata(t *testing.T) {
+	metadata, err := ParseMetadata(strings.NewReader(guavaMetadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.GroupId != "com.google.guava" || metadata.ArtifactId != "guava" {
+		t.Errorf("Unexpected coordinates %s:%s", metadata.GroupId, metadata.ArtifactId)
+	}
+	if len(metadata.Versioning.Versions) != 5 || metadata.Versioning.Release != "33.0.0-jre" {
+		t.Errorf("Unexpected versioning %+v", metadata.Versioning)
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	cases := []struct {
+		versions []string
+		release  string
+		expected string
+	}{
+		{[]string{"1.0", "1.2", "1.10", "2.0-rc1"}, "2.0-rc1", "1.10"},
+		{[]string{"2.0-alpha1", "2.0-M1"}, "2.0-M1", "2.0-M1"},
+		{nil, "1.0", "1.0"},
+		{nil, "", ""},
+	}
+	for _, c := range cases {
+		if latest := LatestRelease(c.versions, c.release); latest != c.expected {
+			t.Errorf("LatestRelease(%v, %q) = %q, expected %q", c.versions, c.release, latest, c.expected)
+		}
+	}
+}
+
+func TestMirrorHttp(t *testing.T) {
+	published := time.Date(2023, 12, 18, 15, 5, 42, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/maven2/com/google/guava/guava/maven-metadata.xml":
+			w.Write([]byte(guavaMetadata))
+		case "/maven2/com/google/guava/guava/33.0.0-jre/guava-33.0.0-jre.pom":
+			if r.Method != http.MethodHead {
+				t.Errorf("Expected HEAD request, got %s", r.Method)
+			}
+			w.Header().Set("Last-Modified", published.Format(http.TimeFormat))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	mirror, err := NewMirror(server.URL + "/maven2/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := mirror.Metadata(context.Background(), "com.google.guava", "guava")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Versioning.Release != "33.0.0-jre" {
+		t.Errorf("Unexpected release %s", metadata.Versioning.Release)
+	}
+
+	modified, err := mirror.Published(context.Background(), "com.google.guava", "guava", "33.0.0-jre")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !modified.Equal(published) {
+		t.Errorf("Expected %s, got %s", published, modified)
+	}
+
+	if _, err := mirror.Metadata(context.Background(), "com.example", "missing"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMirrorFile(t *testing.T) {
+	root := t.TempDir()
+	artifact := filepath.Join(root, "com", "google", "guava", "guava")
+	if err := os.MkdirAll(filepath.Join(artifact, "32.1.0-jre"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(artifact, "maven-metadata.xml"), []byte(guavaMetadata), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pom := filepath.Join(artifact, "32.1.0-jre", "guava-32.1.0-jre.pom")
+	if err := os.WriteFile(pom, []byte("<project/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	published := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(pom, published, published); err != nil {
+		t.Fatal(err)
+	}
+
+	mirror, err := NewMirror("file://" + filepath.ToSlash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := mirror.Metadata(context.Background(), "com.google.guava", "guava")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if LatestRelease(metadata.Versioning.Versions, metadata.Versioning.Release) != "33.0.0-jre" {
+		t.Errorf("Unexpected versions %v", metadata.Versioning.Versions)
+	}
+	modified, err := mirror.Published(context.Background(), "com.google.guava", "guava", "32.1.0-jre")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !modified.Equal(published) {
+		t.Errorf("Expected %s, got %s", published, modified)
+	}
+	if _, err := mirror.Published(context.Background(), "com.google.guava", "guava", "31.1-jre"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMirrorRejectsTraversal(t *testing.T) {
+	mirror, err := NewMirror("file:///srv/maven")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mirror.Metadata(context.Background(), "..", "etc"); err == nil {
+		t.Error("Expected error for traversal coordinates")
+	}
+	if _, err := NewMirror("ftp://example.com/maven"); err == nil {
+		t.Error("Expected error for unsupported scheme")
+	}
+}
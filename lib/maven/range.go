@@ -0,0 +1,130 @@
+package maven
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Restriction is single interval of Maven version range, nil bound means unbounded
+type Restriction struct {
+	Lower          *Version
+	LowerInclusive bool
+	Upper          *Version
+	UpperInclusive bool
+}
+
+// Range is union of restrictions, e.g. "[1.0,2.0),[3.0,)"
+type Range struct {
+	spec         string
+	restrictions []Restriction
+}
+
+// ParseRange parses Maven version range spec as in VersionRange.createFromVersionSpec,
+// plain version without brackets means exactly this version
+func ParseRange(spec string) (*Range, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty version range")
+	}
+	r := &Range{spec: spec}
+
+	if !strings.ContainsAny(spec, "[(") {
+		v := ParseVersion(spec)
+		r.restrictions = []Restriction{{Lower: &v, LowerInclusive: true, Upper: &v, UpperInclusive: true}}
+		return r, nil
+	}
+
+	rest := spec
+	for rest != "" {
+		if rest[0] != '[' && rest[0] != '(' {
+			return nil, fmt.Errorf("malformed version range %q", spec)
+		}
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("unbounded version range %q", spec)
+		}
+		restriction, err := parseRestriction(rest[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed version range %q: %s", spec, err)
+		}
+		r.restrictions = append(r.restrictions, *restriction)
+
+		rest = strings.TrimSpace(rest[end+1:])
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+			if rest == "" {
+				return nil, fmt.Errorf("malformed version range %q", spec)
+			}
+		} else if rest != "" {
+			return nil, fmt.Errorf("malformed version range %q", spec)
+		}
+	}
+	return r, nil
+}
+
+func parseRestriction(spec string) (*Restriction, error) {
+	restriction := &Restriction{
+		LowerInclusive: spec[0] == '[',
+		UpperInclusive: spec[len(spec)-1] == ']',
+	}
+	body := strings.TrimSpace(spec[1 : len(spec)-1])
+
+	if !strings.Contains(body, ",") {
+		// [1.0] is exact version, (1.0) makes no sense
+		if !restriction.LowerInclusive || !restriction.UpperInclusive || body == "" {
+			return nil, fmt.Errorf("single version must be enclosed in []")
+		}
+		v := ParseVersion(body)
+		restriction.Lower, restriction.Upper = &v, &v
+		return restriction, nil
+	}
+
+	bounds := strings.SplitN(body, ",", 2)
+	lower, upper := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+	if strings.Contains(upper, ",") {
+		return nil, fmt.Errorf("too many bounds")
+	}
+	if lower != "" {
+		v := ParseVersion(lower)
+		restriction.Lower = &v
+	}
+	if upper != "" {
+		v := ParseVersion(upper)
+		restriction.Upper = &v
+	}
+	if restriction.Lower != nil && restriction.Upper != nil && restriction.Lower.Compare(*restriction.Upper) > 0 {
+		return nil, fmt.Errorf("lower bound is greater than upper bound")
+	}
+	return restriction, nil
+}
+
+func (r *Range) String() string {
+	return r.spec
+}
+
+// Contains reports whether version matches any restriction of range
+func (r *Range) Contains(version string) bool {
+	v := ParseVersion(version)
+	for _, restriction := range r.restrictions {
+		if restriction.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Restriction) Contains(v Version) bool {
+	if r.Lower != nil {
+		cmp := v.Compare(*r.Lower)
+		if cmp < 0 || (cmp == 0 && !r.LowerInclusive) {
+			return false
+		}
+	}
+	if r.Upper != nil {
+		cmp := v.Compare(*r.Upper)
+		if cmp > 0 || (cmp == 0 && !r.UpperInclusive) {
+			return false
+		}
+	}
+	return true
+}
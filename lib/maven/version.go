@@ -0,0 +1,305 @@
+package maven
+
+import (
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// Version is parsed Maven artifact version. Ordering follows org.apache.maven.artifact.versioning.ComparableVersion:
+// 1.0-alpha < 1.0-beta < 1.0-milestone < 1.0-rc < 1.0-SNAPSHOT < 1.0 < 1.0-sp < 1.0.1
+type Version struct {
+	raw   string
+	items *listItem
+}
+
+type item interface {
+	compare(other item) int
+	isNull() bool
+}
+
+type intItem struct{ value *big.Int }
+
+type stringItem struct{ value string }
+
+type listItem struct{ items []item }
+
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// preReleaseQualifiers are known Maven qualifiers below release plus common early access ones
+var preReleaseQualifiers = map[string]bool{
+	"alpha": true, "beta": true, "milestone": true, "rc": true, "snapshot": true,
+	"ea": true, "preview": true, "dev": true,
+}
+
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+func ParseVersion(version string) Version {
+	return Version{raw: version, items: parseItems(version)}
+}
+
+func (v Version) String() string {
+	return v.raw
+}
+
+// Compare returns -1, 0 or 1 when v is older, equal or newer than other
+func (v Version) Compare(other Version) int {
+	return v.items.compare(other.items)
+}
+
+// CompareVersions compares raw version strings using Maven ordering
+func CompareVersions(a string, b string) int {
+	return ParseVersion(a).Compare(ParseVersion(b))
+}
+
+// IsSnapshot reports whether version is a -SNAPSHOT build
+func IsSnapshot(version string) bool {
+	return strings.HasSuffix(strings.ToUpper(version), "-SNAPSHOT")
+}
+
+// IsPreRelease reports whether version is alpha, beta, milestone, rc, snapshot or early access build, e.g. 2.0.0-M1
+func IsPreRelease(version string) bool {
+	return ParseVersion(version).items.hasPreRelease()
+}
+
+func parseItems(version string) *listItem {
+	version = strings.ToLower(version)
+
+	root := &listItem{}
+	list := root
+	stack := []*listItem{root}
+
+	isDigit := false
+	start := 0
+	runes := []rune(version)
+
+	push := func() {
+		next := &listItem{}
+		list.items = append(list.items, next)
+		list = next
+		stack = append(stack, next)
+	}
+
+	for idx, c := range runes {
+		switch {
+		case c == '.':
+			if idx == start {
+				list.items = append(list.items, intItem{big.NewInt(0)})
+			} else {
+				list.items = append(list.items, parseItem(isDigit, string(runes[start:idx]), false))
+			}
+			start = idx + 1
+		case c == '-':
+			if idx == start {
+				list.items = append(list.items, intItem{big.NewInt(0)})
+			} else {
+				list.items = append(list.items, parseItem(isDigit, string(runes[start:idx]), false))
+			}
+			start = idx + 1
+			push()
+		case unicode.IsDigit(c):
+			if !isDigit && idx > start {
+				list.items = append(list.items, parseItem(false, string(runes[start:idx]), true))
+				start = idx
+				push()
+			}
+			isDigit = true
+		default:
+			if isDigit && idx > start {
+				list.items = append(list.items, parseItem(true, string(runes[start:idx]), false))
+				start = idx
+				push()
+			}
+			isDigit = false
+		}
+	}
+	if len(runes) > start {
+		list.items = append(list.items, parseItem(isDigit, string(runes[start:]), false))
+	}
+
+	for idx := len(stack) - 1; idx >= 0; idx-- {
+		stack[idx].normalize()
+	}
+
+	return root
+}
+
+func parseItem(isDigit bool, value string, followedByDigit bool) item {
+	if isDigit {
+		number, _ := new(big.Int).SetString(strings.TrimLeft(value, "0"), 10)
+		if number == nil {
+			number = big.NewInt(0)
+		}
+		return intItem{number}
+	}
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[value]; ok {
+		value = alias
+	}
+	return stringItem{value}
+}
+
+func (l *listItem) normalize() {
+	for idx := len(l.items) - 1; idx >= 0; idx-- {
+		last := l.items[idx]
+		if last.isNull() {
+			l.items = append(l.items[:idx], l.items[idx+1:]...)
+		} else if _, ok := last.(*listItem); !ok {
+			break
+		}
+	}
+}
+
+func (i intItem) isNull() bool {
+	return i.value.Sign() == 0
+}
+
+func (i intItem) compare(other item) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case intItem:
+		return i.value.Cmp(o.value)
+	default:
+		return 1
+	}
+}
+
+func comparableQualifier(qualifier string) string {
+	for idx, known := range qualifiers {
+		if known == qualifier {
+			return string(rune('0' + idx))
+		}
+	}
+	return string(rune('0'+len(qualifiers))) + "-" + qualifier
+}
+
+func (s stringItem) isNull() bool {
+	return comparableQualifier(s.value) == comparableQualifier("")
+}
+
+func (s stringItem) compare(other item) int {
+	switch o := other.(type) {
+	case nil:
+		return strings.Compare(comparableQualifier(s.value), comparableQualifier(""))
+	case intItem:
+		return -1
+	case stringItem:
+		return strings.Compare(comparableQualifier(s.value), comparableQualifier(o.value))
+	default:
+		return -1
+	}
+}
+
+func (l *listItem) hasPreRelease() bool {
+	for _, it := range l.items {
+		switch i := it.(type) {
+		case stringItem:
+			if preReleaseQualifiers[i.value] {
+				return true
+			}
+		case *listItem:
+			if i.hasPreRelease() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (l *listItem) isNull() bool {
+	return len(l.items) == 0
+}
+
+func (l *listItem) compare(other item) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l.items) == 0 {
+			return 0
+		}
+		return l.items[0].compare(nil)
+	case intItem:
+		return -1
+	case stringItem:
+		return 1
+	case *listItem:
+		for idx := 0; idx < len(l.items) || idx < len(o.items); idx++ {
+			var left, right item
+			if idx < len(l.items) {
+				left = l.items[idx]
+			}
+			if idx < len(o.items) {
+				right = o.items[idx]
+			}
+
+			var result int
+			if left == nil {
+				if right != nil {
+					result = -1 * right.compare(nil)
+				}
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+const (
+	JumpMajor = "major"
+	JumpMinor = "minor"
+	JumpPatch = "patch"
+)
+
+// Jump classifies change between versions by first differing of major.minor.patch numbers, direction is ignored.
+// Versions differing only in qualifier, e.g. 1.0-rc1 and 1.0, are patch jump; equal versions return empty string
+func Jump(from string, to string) string {
+	if CompareVersions(from, to) == 0 {
+		return ""
+	}
+	a, b := numbers(from), numbers(to)
+	switch {
+	case a[0] != b[0]:
+		return JumpMajor
+	case a[1] != b[1]:
+		return JumpMinor
+	default:
+		return JumpPatch
+	}
+}
+
+// numbers returns leading major, minor and patch numbers, missing ones are zero
+func numbers(version string) [3]string {
+	var result [3]string
+	for idx, part := range strings.SplitN(version, ".", 3) {
+		end := strings.IndexFunc(part, func(r rune) bool { return !unicode.IsDigit(r) })
+		if end >= 0 {
+			part = part[:end]
+		}
+		result[idx] = strings.TrimLeft(part, "0")
+		if end >= 0 {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package maven
+
+import "testing"
+
+func TestCompareVersionsOrdering(t *testing.T) {
+	// Ordered list based on Maven ComparableVersionTest
+	ordered := []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+		"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+		"1-1", "1-2", "1-123", "1.1-SNAPSHOT", "1.1", "1.2", "1.10", "2.0.0", "2.0.1",
+	}
+	for i := 0; i < len(ordered); i++ {
+		for j := i + 1; j < len(ordered); j++ {
+			if CompareVersions(ordered[i], ordered[j]) >= 0 {
+				t.Errorf("Expected %s < %s", ordered[i], ordered[j])
+			}
+			if CompareVersions(ordered[j], ordered[i]) <= 0 {
+				t.Errorf("Expected %s > %s", ordered[j], ordered[i])
+			}
+		}
+	}
+}
+
+func TestCompareVersionsEquality(t *testing.T) {
+	equal := [][]string{
+		{"1", "1.0", "1.0.0", "1-0", "1.0-0"},
+		{"1-ga", "1-final", "1-release", "1"},
+		{"1a1", "1-a1", "1.0-alpha-1", "1-alpha1"},
+		{"1b2", "1-beta2", "1.0-beta-2"},
+		{"1m3", "1-milestone3", "1.0-MILESTONE-3"},
+		{"1-cr1", "1-rc1", "1RC1"},
+		{"2.17.1", "2.17.1.0"},
+	}
+	for _, group := range equal {
+		for _, other := range group[1:] {
+			if CompareVersions(group[0], other) != 0 {
+				t.Errorf("Expected %s == %s", group[0], other)
+			}
+		}
+	}
+}
+
+func TestIsSnapshot(t *testing.T) {
+	if !IsSnapshot("1.0-SNAPSHOT") || !IsSnapshot("2.1.0-snapshot") || IsSnapshot("1.0") {
+		t.Error("Wrong snapshot detection")
+	}
+}
+
+func TestIsPreRelease(t *testing.T) {
+	for _, version := range []string{"2.0.0-M1", "1.0-rc2", "1.0RC1", "3.0.0-beta-1", "1.0a1", "1.0-SNAPSHOT", "21-ea", "1.0.0-preview.2"} {
+		if !IsPreRelease(version) {
+			t.Errorf("Expected %s to be pre-release", version)
+		}
+	}
+	for _, version := range []string{"31.1-jre", "5.6.15.Final", "2.17.1", "1.0-android", "1.9.22", "1.0-sp1"} {
+		if IsPreRelease(version) {
+			t.Errorf("Expected %s to be release", version)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	cases := []struct {
+		spec     string
+		version  string
+		expected bool
+	}{
+		{"[2.0-beta9,2.15.0)", "2.14.1", true},
+		{"[2.0-beta9,2.15.0)", "2.15.0", false},
+		{"[2.0-beta9,2.15.0)", "1.2.17", false},
+		{"(,2.17.1)", "2.17.0", true},
+		{"(,2.17.1)", "2.17.1", false},
+		{"[3.0,4.0)", "3.2.2", true},
+		{"[3.0,4.0)", "4.0", false},
+		{"[1.0]", "1.0.0", true},
+		{"[1.0]", "1.0.1", false},
+		{"1.5", "1.5", true},
+		{"(,1.0],[1.2,)", "1.1", false},
+		{"(,1.0],[1.2,)", "1.3", true},
+		{"(1.0,)", "1.0", false},
+	}
+	for _, c := range cases {
+		r, err := ParseRange(c.spec)
+		if err != nil {
+			t.Errorf("ParseRange(%s): %s", c.spec, err)
+			continue
+		}
+		if actual := r.Contains(c.version); actual != c.expected {
+			t.Errorf("%s contains %s = %v", c.spec, c.version, actual)
+		}
+	}
+
+	for _, malformed := range []string{"", "[1.0", "(1.0)", "[2.0,1.0]", "[1.0,2.0),", "[1,2,3]", "[1.0,2.0)x"} {
+		if _, err := ParseRange(malformed); err == nil {
+			t.Errorf("Malformed range %q parsed", malformed)
+		}
+	}
+}
+
+func TestJump(t *testing.T) {
+	cases := []struct {
+		from     string
+		to       string
+		expected string
+	}{
+		{"31.1-jre", "33.0.0-jre", JumpMajor},
+		{"2.0.9", "2.1", JumpMinor},
+		{"2.0.9", "2.0.10", JumpPatch},
+		{"1.0-rc1", "1.0", JumpPatch},
+		{"1.0", "1.0.0", ""},
+		{"1.10", "1.9", JumpMinor},
+		{"3.0.0", "2.9.0", JumpMajor},
+		{"01.2", "1.2.1", JumpPatch},
+	}
+	for _, c := range cases {
+		if jump := Jump(c.from, c.to); jump != c.expected {
+			t.Errorf("Jump(%s, %s) = %q, expected %q", c.from, c.to, jump, c.expected)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package osv
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"sort"
+	"strings"
+)
+
+// Finding is stored dependency version affected by advisory
+type Finding struct {
+	Repo           string   `json:"repo"`
+	Ref            string   `json:"ref"`
+	Dependency     string   `json:"dependency"`
+	Version        string   `json:"version"`
+	Advisory       string   `json:"advisory"`
+	Aliases        []string `json:"aliases,omitempty"`
+	Severity       string   `json:"severity,omitempty"`
+	Summary        string   `json:"summary,omitempty"`
+	Configurations []string `json:"configurations,omitempty"`
+}
+
+// Lookup returns advisories of group:name package
+type Lookup func(pkg string) ([]storage.AdvisoryDto, error)
+
+// Scan matches stored items against advisories, every package is looked up once
+func Scan(items []storage.StorageDto, lookup Lookup) ([]Finding, error) {
+	cache := map[string][]storage.AdvisoryDto{}
+	var result []Finding
+	for _, item := range items {
+		if !isMavenPackage(item) {
+			continue
+		}
+		advisories, found := cache[item.Dependency]
+		if !found {
+			var err error
+			if advisories, err = lookup(item.Dependency); err != nil {
+				return nil, err
+			}
+			cache[item.Dependency] = advisories
+		}
+		for _, advisory := range advisories {
+			if Matches(advisory, item.Version) {
+				result = append(result, Finding{
+					Repo:           item.Repo,
+					Ref:            item.Ref,
+					Dependency:     item.Dependency,
+					Version:        item.Version,
+					Advisory:       advisory.Id,
+					Aliases:        advisory.Aliases,
+					Severity:       advisory.Severity,
+					Summary:        advisory.Summary,
+					Configurations: item.Configurations,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		if a.Ref != b.Ref {
+			return a.Ref < b.Ref
+		}
+		if a.Dependency != b.Dependency {
+			return a.Dependency < b.Dependency
+		}
+		return a.Advisory < b.Advisory
+	})
+	return result, nil
+}
+
+// plugins and environment items are not Maven artifacts
+func isMavenPackage(item storage.StorageDto) bool {
+	if item.Kind == storage.KindPlugin || item.Kind == storage.KindEnvironment {
+		return false
+	}
+	return strings.Contains(item.Dependency, ":") && !strings.HasPrefix(item.Dependency, "-")
+}
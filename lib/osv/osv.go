@@ -0,0 +1,276 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const EcosystemMaven = "Maven"
+
+// Advisory is subset of OSV schema https://ossf.github.io/osv-schema/ used for matching
+type Advisory struct {
+	Id        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Modified  string   `json:"modified"`
+	Withdrawn string   `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected         []Affected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string  `json:"type"`
+		Events []Event `json:"events"`
+	} `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Load reads Maven advisories from OSV zip (as downloaded from osv-vulnerabilities bucket) or directory of json files
+func Load(path string) ([]Advisory, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Advisory
+	add := func(name string, r io.Reader) error {
+		var adv Advisory
+		if err := json.NewDecoder(r).Decode(&adv); err != nil {
+			return errors.Wrapf(err, "malformed advisory %s", name)
+		}
+		if adv.Withdrawn == "" && adv.affectsMaven() {
+			result = append(result, adv)
+		}
+		return nil
+	}
+
+	if info.IsDir() {
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !strings.HasSuffix(file, ".json") {
+				return err
+			}
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return add(file, f)
+		})
+		return result, err
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		err = add(file.Name, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (adv Advisory) affectsMaven() bool {
+	for _, affected := range adv.Affected {
+		if affected.Package.Ecosystem == EcosystemMaven {
+			return true
+		}
+	}
+	return false
+}
+
+// SeverityName prefers GHSA severity (LOW..CRITICAL) over CVSS vector
+func (adv Advisory) SeverityName() string {
+	if adv.DatabaseSpecific.Severity != "" {
+		return strings.ToUpper(adv.DatabaseSpecific.Severity)
+	}
+	if len(adv.Severity) > 0 {
+		return adv.Severity[0].Score
+	}
+	return ""
+}
+
+// Items converts advisory into per package items and alias items for lookup by id, CVE or GHSA.
+// GHSA lists package in several affected entries (one per range), ranges are merged into single item of package
+func (adv Advisory) Items() []storage.AdvisoryDto {
+	var packages []string
+	ranges := map[string][]string{}
+	for _, affected := range adv.Affected {
+		if affected.Package.Ecosystem != EcosystemMaven {
+			continue
+		}
+		name := affected.Package.Name
+		if _, found := ranges[name]; !found {
+			packages = append(packages, name)
+		}
+		ranges[name] = append(ranges[name], affected.MavenRanges()...)
+	}
+
+	var items []storage.AdvisoryDto
+	var affectedPackages []string
+	for _, name := range packages {
+		if len(ranges[name]) == 0 {
+			continue
+		}
+		affectedPackages = append(affectedPackages, name)
+		items = append(items, storage.AdvisoryDto{
+			Package:  name,
+			Id:       adv.Id,
+			Aliases:  adv.Aliases,
+			Summary:  adv.Summary,
+			Severity: adv.SeverityName(),
+			Ranges:   uniqueSorted(ranges[name]),
+			Modified: adv.Modified,
+		})
+	}
+	if len(affectedPackages) == 0 {
+		return nil
+	}
+
+	for _, alias := range append([]string{adv.Id}, adv.Aliases...) {
+		items = append(items, storage.AdvisoryDto{
+			Package:  storage.AliasPrefix + alias,
+			Id:       adv.Id,
+			Packages: uniqueSorted(affectedPackages),
+			Modified: adv.Modified,
+		})
+	}
+	return items
+}
+
+// MavenRanges converts ECOSYSTEM ranges and listed versions of affected entry into Maven range specs
+func (affected Affected) MavenRanges() []string {
+	var ranges []string
+	for _, r := range affected.Ranges {
+		if r.Type == "ECOSYSTEM" {
+			ranges = append(ranges, EventsToRanges(r.Events)...)
+		}
+	}
+	for _, version := range affected.Versions {
+		ranges = append(ranges, fmt.Sprintf("[%s]", version))
+	}
+	return ranges
+}
+
+// EventsToRanges converts OSV ECOSYSTEM events into Maven range specs, introduced "0" means no lower bound
+func EventsToRanges(events []Event) []string {
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareEvents(sorted[i], sorted[j]) < 0
+	})
+
+	var ranges []string
+	var introduced *string
+	lower := func() string {
+		if *introduced == "0" {
+			return "(,"
+		}
+		return "[" + *introduced + ","
+	}
+	for _, event := range sorted {
+		switch {
+		case event.Introduced != "":
+			if introduced == nil {
+				value := event.Introduced
+				introduced = &value
+			}
+		case event.Fixed != "" && introduced != nil:
+			ranges = append(ranges, lower()+event.Fixed+")")
+			introduced = nil
+		case event.LastAffected != "" && introduced != nil:
+			ranges = append(ranges, lower()+event.LastAffected+"]")
+			introduced = nil
+		}
+	}
+	if introduced != nil {
+		if *introduced == "0" {
+			ranges = append(ranges, "(,)")
+		} else {
+			ranges = append(ranges, "["+*introduced+",)")
+		}
+	}
+	return ranges
+}
+
+func eventVersion(event Event) string {
+	for _, v := range []string{event.Introduced, event.Fixed, event.LastAffected, event.Limit} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func compareEvents(a Event, b Event) int {
+	va, vb := eventVersion(a), eventVersion(b)
+	if va == "0" || vb == "0" {
+		if va == vb {
+			return 0
+		}
+		if va == "0" {
+			return -1
+		}
+		return 1
+	}
+	return maven.CompareVersions(va, vb)
+}
+
+// Matches reports whether version is within any range of advisory item
+func Matches(advisory storage.AdvisoryDto, version string) bool {
+	for _, spec := range advisory.Ranges {
+		r, err := maven.ParseRange(spec)
+		if err != nil {
+			continue
+		}
+		if r.Contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueSorted(values []string) []string {
+	result := helpers.Unique(values)
+	sort.Strings(result)
+	return result
+}
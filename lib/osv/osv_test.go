@@ -0,0 +1,121 @@
+package osv
+
+import (
+	"archive/zip"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEventsToRanges(t *testing.T) {
+	cases := []struct {
+		events   []Event
+		expected []string
+	}{
+		{[]Event{{Introduced: "2.13.0"}, {Fixed: "2.15.0"}}, []string{"[2.13.0,2.15.0)"}},
+		{[]Event{{Fixed: "2.12.2"}, {Introduced: "2.0-beta9"}}, []string{"[2.0-beta9,2.12.2)"}},
+		{[]Event{{Introduced: "0"}, {LastAffected: "1.11.9"}}, []string{"(,1.11.9]"}},
+		{[]Event{{Introduced: "1.0"}, {Fixed: "1.2"}, {Introduced: "2.0"}}, []string{"[1.0,1.2)", "[2.0,)"}},
+		{[]Event{{Introduced: "0"}}, []string{"(,)"}},
+	}
+	for _, c := range cases {
+		if actual := EventsToRanges(c.events); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("EventsToRanges(%+v) = %v", c.events, actual)
+		}
+	}
+}
+
+func loadItems(t *testing.T, path string) map[string]storage.AdvisoryDto {
+	advisories, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(advisories) != 1 {
+		t.Fatalf("Wrong advisories count %d", len(advisories))
+	}
+	items := map[string]storage.AdvisoryDto{}
+	for _, item := range advisories[0].Items() {
+		if _, found := items[item.Package]; found {
+			t.Fatalf("Duplicate item of %s", item.Package)
+		}
+		items[item.Package] = item
+	}
+	return items
+}
+
+func TestLoadAndMatch(t *testing.T) {
+	items := loadItems(t, "testdata")
+	if len(items) != 4 {
+		t.Fatalf("Wrong items %+v", items)
+	}
+	alias := items[storage.AliasPrefix+"CVE-2021-44228"]
+	if alias.Id != "GHSA-jfh8-c2jp-5v3q" || len(alias.Packages) != 2 {
+		t.Errorf("Wrong alias item %+v", alias)
+	}
+
+	log4j := items["org.apache.logging.log4j:log4j-core"]
+	if !reflect.DeepEqual(log4j.Ranges, []string{"[2.0-beta9,2.12.2)", "[2.13.0,2.15.0)"}) {
+		t.Errorf("Ranges of affected entries are not merged %v", log4j.Ranges)
+	}
+	if log4j.Severity != "CRITICAL" {
+		t.Errorf("Wrong severity %s", log4j.Severity)
+	}
+	versions := map[string]bool{"2.14.1": true, "2.12.1": true, "2.0-beta9": true, "2.12.2": false, "2.15.0": false, "1.2.17": false, "2.17.1": false}
+	for version, expected := range versions {
+		if actual := Matches(log4j, version); actual != expected {
+			t.Errorf("log4j-core %s affected = %v", version, actual)
+		}
+	}
+	pax := items["org.ops4j.pax.logging:pax-logging-log4j2"]
+	if !Matches(pax, "1.11.9") || !Matches(pax, "1.12.0") || Matches(pax, "1.12.1") {
+		t.Errorf("Wrong pax-logging ranges %v", pax.Ranges)
+	}
+}
+
+func TestLoadZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all.zip")
+	file, _ := os.Create(path)
+	w := zip.NewWriter(file)
+	for _, name := range []string{"GHSA-jfh8-c2jp-5v3q.json", "GHSA-npm-only.json"} {
+		data, _ := ioutil.ReadFile(filepath.Join("testdata", name))
+		f, _ := w.Create(name)
+		f.Write(data)
+	}
+	w.Close()
+	file.Close()
+
+	if items := loadItems(t, path); len(items) != 4 {
+		t.Errorf("Wrong items from zip %+v", items)
+	}
+}
+
+func TestScan(t *testing.T) {
+	items := loadItems(t, "testdata")
+	lookups := 0
+	lookup := func(pkg string) ([]storage.AdvisoryDto, error) {
+		lookups++
+		if item, found := items[pkg]; found {
+			return []storage.AdvisoryDto{item}, nil
+		}
+		return nil, nil
+	}
+
+	findings, err := Scan([]storage.StorageDto{
+		{Dependency: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Repo: "acme/b", Ref: "main", Kind: storage.KindLibrary},
+		{Dependency: "org.apache.logging.log4j:log4j-core", Version: "2.17.1", Repo: "acme/a", Ref: "main", Kind: storage.KindLibrary},
+		{Dependency: "org.apache.logging.log4j:log4j-core", Version: "2.13.3", Repo: "acme/a", Ref: "old", Kind: storage.KindLibrary},
+		{Dependency: "-environment:gradle", Version: "8.4", Repo: "acme/a", Ref: "main", Kind: storage.KindEnvironment},
+	}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lookups != 1 {
+		t.Errorf("Package is looked up %d times", lookups)
+	}
+	if len(findings) != 2 || findings[0].Repo != "acme/a" || findings[0].Ref != "old" || findings[1].Repo != "acme/b" {
+		t.Errorf("Wrong findings %+v", findings)
+	}
+}
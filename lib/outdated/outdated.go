@@ -0,0 +1,197 @@
+package outdated
+
+import (
+	"context"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTTL     = 24 * time.Hour
+	DefaultWorkers = 16
+)
+
+// Source is Maven repository, maven.Mirror in production
+type Source interface {
+	Metadata(ctx context.Context, group string, name string) (*maven.Metadata, error)
+	Published(ctx context.Context, group string, name string, version string) (time.Time, error)
+}
+
+// Cache keeps Source answers, storage.Storage in production
+type Cache interface {
+	GetMetadata(ctxId string, keys []storage.MetadataDto) (map[string]storage.MetadataDto, *storage.StorageErrorRest)
+	PutMetadata(ctxId string, items []storage.MetadataDto) (*storage.UpsertResultRest, *storage.StorageErrorRest)
+}
+
+// Resolver annotates stored dependencies with newer releases, artifacts not fetched before ctx deadline stay unannotated
+type Resolver struct {
+	Source  Source
+	Cache   Cache
+	TTL     time.Duration
+	Workers int
+}
+
+func NewResolver(source Source, cache Cache) *Resolver {
+	return &Resolver{Source: source, Cache: cache, TTL: DefaultTTL, Workers: DefaultWorkers}
+}
+
+// Annotate sets Outdated of library and platform items
+func (r *Resolver) Annotate(ctx context.Context, ctxId string, items []storage.StorageDto, now time.Time) error {
+	var keys []storage.MetadataDto
+	seen := map[string]bool{}
+	for _, item := range items {
+		if isMaven(item) && !seen[item.Dependency] {
+			seen[item.Dependency] = true
+			keys = append(keys, storage.MetadataDto{Dependency: item.Dependency, Version: storage.MetadataAnyVersion})
+		}
+	}
+	artifacts, err := r.resolve(ctx, ctxId, keys, now)
+	if err != nil {
+		return err
+	}
+
+	// Second round needs latest release known from artifact metadata
+	keys = keys[:0]
+	seen = map[string]bool{}
+	for _, item := range items {
+		artifact, ok := artifacts[storage.VersionKey(item.Dependency, storage.MetadataAnyVersion)]
+		if !isMaven(item) || !ok || artifact.Missing {
+			continue
+		}
+		for _, version := range []string{item.Version, maven.LatestRelease(artifact.Versions, artifact.Release)} {
+			if key := storage.VersionKey(item.Dependency, version); version != "" && !seen[key] {
+				seen[key] = true
+				keys = append(keys, storage.MetadataDto{Dependency: item.Dependency, Version: version})
+			}
+		}
+	}
+	versions, err := r.resolve(ctx, ctxId, keys, now)
+	if err != nil {
+		return err
+	}
+
+	for idx, item := range items {
+		artifact, ok := artifacts[storage.VersionKey(item.Dependency, storage.MetadataAnyVersion)]
+		if !isMaven(item) || !ok || artifact.Missing {
+			continue
+		}
+		items[idx].Outdated = Compare(item.Version, artifact,
+			versions[storage.VersionKey(item.Dependency, item.Version)],
+			versions[storage.VersionKey(item.Dependency, maven.LatestRelease(artifact.Versions, artifact.Release))],
+			now,
+		)
+	}
+	return nil
+}
+
+// Compare builds annotation from cached artifact metadata and publication times of used and latest versions
+func Compare(version string, artifact storage.MetadataDto, used storage.MetadataDto, latest storage.MetadataDto, now time.Time) *storage.OutdatedDto {
+	result := &storage.OutdatedDto{
+		Latest:         maven.LatestRelease(artifact.Versions, artifact.Release),
+		Released:       used.Published,
+		LatestReleased: latest.Published,
+	}
+	if result.Latest == "" {
+		return nil
+	}
+	for _, candidate := range artifact.Versions {
+		if !maven.IsPreRelease(candidate) && maven.CompareVersions(candidate, version) > 0 {
+			result.Behind++
+		}
+	}
+	if released, err := time.Parse(time.RFC3339, used.Published); err == nil {
+		result.AgeDays = int(now.Sub(released).Hours() / 24)
+	}
+	return result
+}
+
+// resolve returns cached items, fetching missing and expired ones from Source in parallel
+func (r *Resolver) resolve(ctx context.Context, ctxId string, keys []storage.MetadataDto, now time.Time) (map[string]storage.MetadataDto, error) {
+	if len(keys) == 0 {
+		return map[string]storage.MetadataDto{}, nil
+	}
+	cached, errCache := r.Cache.GetMetadata(ctxId, keys)
+	if errCache != nil {
+		return nil, errCache.Err
+	}
+
+	var stale []storage.MetadataDto
+	for _, key := range keys {
+		if item, ok := cached[storage.VersionKey(key.Dependency, key.Version)]; !ok || item.Expires <= now.Unix() {
+			stale = append(stale, key)
+		}
+	}
+
+	var mutex sync.Mutex
+	var fetched []storage.MetadataDto
+	work := make(chan storage.MetadataDto)
+	var wg sync.WaitGroup
+	for worker := 0; worker < r.Workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range work {
+				if item, ok := r.fetch(ctx, key, now); ok {
+					mutex.Lock()
+					fetched = append(fetched, item)
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	for _, key := range stale {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- key
+	}
+	close(work)
+	wg.Wait()
+
+	if len(fetched) > 0 {
+		if _, errCache := r.Cache.PutMetadata(ctxId, fetched); errCache != nil {
+			return nil, errCache.Err
+		}
+	}
+	for _, item := range fetched {
+		cached[storage.VersionKey(item.Dependency, item.Version)] = item
+	}
+	return cached, nil
+}
+
+// fetch asks Source, not found artifacts are cached as Missing while other errors are retried next time
+func (r *Resolver) fetch(ctx context.Context, key storage.MetadataDto, now time.Time) (storage.MetadataDto, bool) {
+	parts := strings.SplitN(key.Dependency, ":", 2)
+	if len(parts) != 2 {
+		return key, false
+	}
+	key.Checked = now.UTC().Format(time.RFC3339)
+	key.Expires = now.Add(r.TTL).Unix()
+
+	var err error
+	if key.Version == storage.MetadataAnyVersion {
+		var metadata *maven.Metadata
+		if metadata, err = r.Source.Metadata(ctx, parts[0], parts[1]); err == nil {
+			key.Versions = metadata.Versioning.Versions
+			key.Release = metadata.Versioning.Release
+		}
+	} else {
+		var published time.Time
+		if published, err = r.Source.Published(ctx, parts[0], parts[1], key.Version); err == nil {
+			key.Published = published.UTC().Format(time.RFC3339)
+		}
+	}
+
+	if err == maven.ErrNotFound {
+		key.Missing = true
+		return key, true
+	}
+	return key, err == nil
+}
+
+func isMaven(item storage.StorageDto) bool {
+	return item.Kind == "" || item.Kind == storage.KindLibrary || item.Kind == storage.KindPlatform
+}
@@ -0,0 +1,136 @@
+package outdated
+
+import (
+	"context"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	versions  map[string][]string
+	published map[string]time.Time
+	calls     int
+}
+
+func (f *fakeSource) Metadata(_ context.Context, group string, name string) (*maven.Metadata, error) {
+	f.calls++
+	versions, ok := f.versions[group+":"+name]
+	if !ok {
+		return nil, maven.ErrNotFound
+	}
+	metadata := &maven.Metadata{GroupId: group, ArtifactId: name}
+	metadata.Versioning.Versions = versions
+	return metadata, nil
+}
+
+func (f *fakeSource) Published(_ context.Context, group string, name string, version string) (time.Time, error) {
+	f.calls++
+	published, ok := f.published[group+":"+name+":"+version]
+	if !ok {
+		return time.Time{}, maven.ErrNotFound
+	}
+	return published, nil
+}
+
+type fakeCache map[string]storage.MetadataDto
+
+func (f fakeCache) GetMetadata(_ string, keys []storage.MetadataDto) (map[string]storage.MetadataDto, *storage.StorageErrorRest) {
+	result := map[string]storage.MetadataDto{}
+	for _, key := range keys {
+		if item, ok := f[storage.VersionKey(key.Dependency, key.Version)]; ok {
+			result[storage.VersionKey(key.Dependency, key.Version)] = item
+		}
+	}
+	return result, nil
+}
+
+func (f fakeCache) PutMetadata(_ string, items []storage.MetadataDto) (*storage.UpsertResultRest, *storage.StorageErrorRest) {
+	for _, item := range items {
+		f[storage.VersionKey(item.Dependency, item.Version)] = item
+	}
+	return &storage.UpsertResultRest{}, nil
+}
+
+func TestAnnotate(t *testing.T) {
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	source := &fakeSource{
+		versions: map[string][]string{
+			"com.google.guava:guava": {"31.1-jre", "32.0.0-jre", "32.1.0-jre", "33.0.0-jre", "34.0.0-rc1"},
+		},
+		published: map[string]time.Time{
+			"com.google.guava:guava:31.1-jre":   time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC),
+			"com.google.guava:guava:33.0.0-jre": time.Date(2023, 12, 18, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	cache := fakeCache{}
+	resolver := NewResolver(source, cache)
+
+	items := []storage.StorageDto{
+		{Dependency: "com.google.guava:guava", Version: "31.1-jre", Kind: storage.KindLibrary},
+		{Dependency: "com.example:internal", Version: "1.0", Kind: storage.KindLibrary},
+		{Dependency: "org.jetbrains.kotlin.jvm", Version: "1.9.0", Kind: storage.KindPlugin},
+	}
+	if err := resolver.Annotate(context.Background(), "test", items, now); err != nil {
+		t.Fatal(err)
+	}
+
+	outdated := items[0].Outdated
+	if outdated == nil {
+		t.Fatal("Expected guava to be annotated")
+	}
+	if outdated.Latest != "33.0.0-jre" || outdated.Behind != 3 {
+		t.Errorf("Unexpected annotation %+v", outdated)
+	}
+	if outdated.AgeDays != 702 || outdated.LatestReleased != "2023-12-18T00:00:00Z" {
+		t.Errorf("Unexpected release times %+v", outdated)
+	}
+	if items[1].Outdated != nil || items[2].Outdated != nil {
+		t.Errorf("Expected missing and plugin dependencies not annotated")
+	}
+	if item := cache[storage.VersionKey("com.example:internal", storage.MetadataAnyVersion)]; !item.Missing {
+		t.Errorf("Expected missing artifact to be cached, got %+v", item)
+	}
+
+	// Second call is served from cache until TTL expires
+	calls := source.calls
+	items[0].Outdated = nil
+	if err := resolver.Annotate(context.Background(), "test", items, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if source.calls != calls || items[0].Outdated == nil {
+		t.Errorf("Expected cached annotation without source calls, got %d calls", source.calls-calls)
+	}
+	if err := resolver.Annotate(context.Background(), "test", items, now.Add(2*DefaultTTL)); err != nil {
+		t.Fatal(err)
+	}
+	if source.calls == calls {
+		t.Error("Expected expired cache to be refreshed")
+	}
+}
+
+func TestAnnotateCancelled(t *testing.T) {
+	source := &fakeSource{versions: map[string][]string{"com.google.guava:guava": {"33.0.0-jre"}}}
+	resolver := NewResolver(source, fakeCache{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	items := []storage.StorageDto{{Dependency: "com.google.guava:guava", Version: "31.1-jre", Kind: storage.KindLibrary}}
+	if err := resolver.Annotate(ctx, "test", items, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if items[0].Outdated != nil || source.calls != 0 {
+		t.Errorf("Expected no lookups after deadline, got %+v", items[0].Outdated)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	artifact := storage.MetadataDto{Versions: []string{"1.0", "1.1", "2.0-beta1"}}
+	if outdated := Compare("1.1", artifact, storage.MetadataDto{}, storage.MetadataDto{}, time.Now()); outdated.Behind != 0 || outdated.Latest != "1.1" {
+		t.Errorf("Expected up to date, got %+v", outdated)
+	}
+	if outdated := Compare("1.0", storage.MetadataDto{}, storage.MetadataDto{}, storage.MetadataDto{}, time.Now()); outdated != nil {
+		t.Errorf("Expected nil without versions, got %+v", outdated)
+	}
+}
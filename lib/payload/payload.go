@@ -0,0 +1,16 @@
+package payload
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"gradle-serverless-dependencies-graph/lib/storage"
+)
+
+// Decode parses request body of PUT /api/v1/repository/{org}/{repo}/{ref+}
+func Decode(body []byte) (*storage.DependenciesRest, error) {
+	var deps storage.DependenciesRest
+	if err := json.Unmarshal(body, &deps); err != nil {
+		return nil, errors.Wrap(err, "malformed dependencies payload")
+	}
+	return &deps, nil
+}
@@ -0,0 +1,216 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const (
+	initScript  = "../../gradle/dependencies-graph.init.gradle"
+	initPayload = "../../gradle/testdata/payload.json"
+)
+
+func TestDecode(t *testing.T) {
+	body, err := ioutil.ReadFile(initPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps, err := Decode(body)
+	if err != nil {
+		t.Fatal("Error", err)
+	}
+	if len(deps.Dependencies) != 4 || len(deps.Plugins) != 1 || len(deps.Edges) != 4 {
+		t.Errorf("Wrong payload content %+v", deps)
+	}
+	if deps.Dependencies[0].Platform != storage.PlatformRegular {
+		t.Error("Platform is not decoded", deps.Dependencies[0])
+	}
+	if deps.Dependencies[3].Variant == nil || deps.Dependencies[3].Variant.Name() != "freeRelease" {
+		t.Error("Variant is not decoded", deps.Dependencies[3])
+	}
+	if deps.Environment == nil || deps.Environment.Versions()[storage.ToolJava] != "17" {
+		t.Error("Environment is not decoded", deps.Environment)
+	}
+	if !reflect.DeepEqual(deps.Produces, []string{"com.acme:demo-client"}) {
+		t.Error("Produces is not decoded", deps.Produces)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	if _, err := Decode([]byte(`{"dependencies": {}}`)); err == nil {
+		t.Error("Error is nil")
+	}
+}
+
+// Sample payload is init script output of a sample build, refresh it with DEPENDENCIES_GRAPH_OUTPUT when script
+// changes. Keys written by script and keys of sample must be the same, and sample must pass server decoding
+func TestInitScriptPayload(t *testing.T) {
+	script, err := ioutil.ReadFile(initScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadFile(initPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	known := make(map[string]bool)
+	collectJsonKeys(reflect.TypeOf(storage.DependenciesRest{}), known)
+
+	scriptKeys := make(map[string]bool)
+	for _, pattern := range []string{`'([a-z-]+)'\s*:`, `\['([a-z-]+)'\]\s*=[^=]`} {
+		for _, key := range regexp.MustCompile(pattern).FindAllSubmatch(script, -1) {
+			scriptKeys[string(key[1])] = true
+		}
+	}
+	if len(scriptKeys) == 0 {
+		t.Fatal("No payload keys found in init script")
+	}
+
+	var sample interface{}
+	if err := json.Unmarshal(body, &sample); err != nil {
+		t.Fatal(err)
+	}
+	sampleKeys := make(map[string]bool)
+	collectSampleKeys(sample, sampleKeys)
+
+	for key := range scriptKeys {
+		if !known[key] {
+			t.Errorf("Init script sends %q which is unknown to server", key)
+		}
+		if !sampleKeys[key] {
+			t.Errorf("Init script sends %q which is missing in sample payload", key)
+		}
+	}
+	for key := range sampleKeys {
+		if !scriptKeys[key] {
+			t.Errorf("Sample payload has %q which init script does not send", key)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	var deps storage.DependenciesRest
+	if err := decoder.Decode(&deps); err != nil {
+		t.Fatal("Sample payload does not match server model", err)
+	}
+	if err := Validate(&deps); err != nil {
+		t.Errorf("Sample payload is invalid: %+v", err.Violations)
+	}
+}
+
+func collectSampleKeys(value interface{}, keys map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			keys[key] = true
+			collectSampleKeys(nested, keys)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			collectSampleKeys(nested, keys)
+		}
+	}
+}
+
+func collectJsonKeys(typ reflect.Type, keys map[string]bool) {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+			keys[name] = true
+		}
+		collectJsonKeys(field.Type, keys)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	body, err := ioutil.ReadFile(initPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps, _ := Decode(body)
+	if err := Validate(deps); err != nil {
+		t.Fatalf("Init script payload is invalid: %+v", err.Violations)
+	}
+
+	invalid := &storage.DependenciesRest{
+		Dependencies: []storage.DependencyRest{
+			{Group: "com.acme", Name: "lib", Version: "", Licenses: []string{"MIT", " "}},
+			{Group: "com:acme", Name: "lib", Version: "1.0", Platform: "bom", Location: &storage.LocationRest{File: "../build.gradle"}},
+			{Group: "com.acme", Name: "lib", Version: "2.0"},
+			{Group: "com.acme", Name: "lib", Version: "2.0", Variant: &storage.VariantRest{BuildType: "release"}},
+		},
+		Plugins:  []storage.PluginRest{{Id: "com.acme.plugin", Version: "1.0\n"}},
+		Edges:    []storage.EdgeRest{{From: "", To: "com.acme"}},
+		Produces: []string{"com.acme:lib:1.0"},
+	}
+	verr := Validate(invalid)
+	if verr == nil || verr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Wrong validation result %+v", verr)
+	}
+	expected := []string{
+		"dependencies[0].version",
+		"dependencies[0].licenses",
+		"dependencies[1].group",
+		"dependencies[1].platform",
+		"dependencies[1].location.file",
+		"dependencies[2].version",
+		"plugins[0].version",
+		"edges[0].to",
+		"produces[0].artifact",
+	}
+	var actual []string
+	for _, v := range verr.Violations {
+		actual = append(actual, v.Entry+"."+v.Field)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Wrong violations %v", actual)
+	}
+
+	tooMany := &storage.DependenciesRest{Edges: make([]storage.EdgeRest, MaxEntries+1)}
+	if verr := Validate(tooMany); verr == nil || verr.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Too many entries accepted")
+	}
+	if verr := ValidateSize(make([]byte, MaxPayloadBytes+1)); verr == nil || verr.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Too large payload accepted")
+	}
+}
+
+func TestValidateProducers(t *testing.T) {
+	linked := map[string]storage.ProducerDto{
+		"com.acme:app-api": {Artifact: "com.acme:app-api", Repo: "acme/app"},
+		"com.acme:core":    {Artifact: "com.acme:core", Repo: "acme/core"},
+	}
+	if err := ValidateProducers("acme/app", []string{"com.acme:app-api", "com.acme:app-new"}, linked); err != nil {
+		t.Errorf("Own artifacts rejected %+v", err.Violations)
+	}
+	err := ValidateProducers("acme/app", []string{"com.acme:app-api", "com.acme:core"}, linked)
+	if err == nil || err.StatusCode != http.StatusConflict || len(err.Violations) != 1 || err.Violations[0].Entry != "produces[1]" {
+		t.Errorf("Artifact of another repository accepted %+v", err)
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	if err := ValidatePath("acme", "app", "feature/new-api"); err != nil {
+		t.Errorf("Valid path rejected %+v", err.Violations)
+	}
+	for _, ref := range []string{"", "a:b", "main\x00", strings.Repeat("x", MaxPathLength+1)} {
+		if err := ValidatePath("acme", "app", ref); err == nil || err.StatusCode != http.StatusBadRequest {
+			t.Errorf("Invalid ref %q accepted", ref)
+		}
+	}
+}
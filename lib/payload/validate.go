@@ -0,0 +1,252 @@
+package payload
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxPayloadBytes = 5 << 20
+	MaxEntries      = 20000
+	MaxPathLength   = 255
+	// MaxViolations limits response size, first violations are enough to fix the producer
+	MaxViolations = 100
+)
+
+var (
+	coordinateRegexp = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+	versionRegexp    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.+-]*$`)
+	variantRegexp    = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+// Violation describes single invalid field, Entry is json path of entry, e.g. dependencies[3]
+type Violation struct {
+	Entry   string `json:"entry"`
+	Field   string `json:"field"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned to client as is with StatusCode
+type ValidationError struct {
+	StatusCode int         `json:"-"`
+	Status     string      `json:"status"`
+	Violations []Violation `json:"errors,omitempty"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %d violations", e.Status, len(e.Violations))
+}
+
+func (e *ValidationError) add(entry string, field string, value string, message string) {
+	if len(e.Violations) < MaxViolations {
+		e.Violations = append(e.Violations, Violation{Entry: entry, Field: field, Value: value, Message: message})
+	}
+}
+
+// ValidatePath checks org, repo and ref which become parts of ':' joined storage keys
+func ValidatePath(org string, repo string, ref string) *ValidationError {
+	err := &ValidationError{StatusCode: http.StatusBadRequest, Status: "Invalid repository path"}
+	for _, part := range []struct{ name, value string }{{"org", org}, {"repo", repo}, {"ref", ref}} {
+		if msg := checkKeyPart(part.value); msg != "" {
+			err.add("path", part.name, printable(part.value), msg)
+		}
+	}
+	if len(err.Violations) > 0 {
+		return err
+	}
+	return nil
+}
+
+// ValidateSize rejects body before decoding
+func ValidateSize(body []byte) *ValidationError {
+	if len(body) > MaxPayloadBytes {
+		return &ValidationError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Status:     fmt.Sprintf("Payload exceeds %d bytes", MaxPayloadBytes),
+		}
+	}
+	return nil
+}
+
+// Validate checks entries count and syntax of every entry of decoded payload
+func Validate(deps *storage.DependenciesRest) *ValidationError {
+	if entries := len(deps.Dependencies) + len(deps.Plugins) + len(deps.Edges) + len(deps.Produces); entries > MaxEntries {
+		return &ValidationError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Status:     fmt.Sprintf("Payload has %d entries, maximum is %d", entries, MaxEntries),
+		}
+	}
+
+	err := &ValidationError{StatusCode: http.StatusUnprocessableEntity, Status: "Invalid dependencies"}
+	// Storage keeps single item per group:name and variant, so other versions would be lost silently
+	seen := make(map[string]string, len(deps.Dependencies))
+	for idx, dep := range deps.Dependencies {
+		entry := fmt.Sprintf("dependencies[%d]", idx)
+		key := fmt.Sprintf("%s:%s", dep.Group, dep.Name)
+		if dep.Variant != nil && dep.Variant.Name() != "" {
+			key = fmt.Sprintf("%s@%s", key, dep.Variant.Name())
+		}
+		if first, found := seen[key]; found {
+			err.add(entry, "version", printable(dep.Version), fmt.Sprintf("duplicates %s, single version of dependency per variant is stored", first))
+		} else {
+			seen[key] = entry
+		}
+		checkPattern(err, entry, "group", dep.Group, coordinateRegexp)
+		checkPattern(err, entry, "name", dep.Name, coordinateRegexp)
+		checkPattern(err, entry, "version", dep.Version, versionRegexp)
+		if dep.Platform != "" && dep.Platform != storage.PlatformRegular && dep.Platform != storage.PlatformEnforced {
+			err.add(entry, "platform", dep.Platform, fmt.Sprintf("must be %s or %s", storage.PlatformRegular, storage.PlatformEnforced))
+		}
+		if dep.ManagedBy != "" {
+			checkCoordinate(err, entry, "managed-by", dep.ManagedBy)
+		}
+		if dep.ManagedVersion != "" {
+			checkPattern(err, entry, "managed-version", dep.ManagedVersion, versionRegexp)
+		}
+		for _, conf := range dep.Configurations {
+			checkPattern(err, entry, "configurations", conf, coordinateRegexp)
+		}
+		for _, license := range dep.Licenses {
+			if msg := checkText(license); msg != "" {
+				err.add(entry, "licenses", printable(license), msg)
+			}
+		}
+		if dep.Location != nil {
+			if msg := checkLocation(dep.Location.File); msg != "" {
+				err.add(entry, "location.file", printable(dep.Location.File), msg)
+			}
+			if dep.Location.Line < 0 {
+				err.add(entry, "location.line", fmt.Sprint(dep.Location.Line), "must not be negative")
+			}
+		}
+		if dep.Variant != nil {
+			checkPattern(err, entry, "variant.build-type", dep.Variant.BuildType, variantRegexp)
+			for _, flavor := range dep.Variant.Flavors {
+				checkPattern(err, entry, "variant.flavors", flavor, variantRegexp)
+			}
+		}
+	}
+
+	for idx, plugin := range deps.Plugins {
+		entry := fmt.Sprintf("plugins[%d]", idx)
+		checkPattern(err, entry, "id", plugin.Id, coordinateRegexp)
+		checkPattern(err, entry, "version", plugin.Version, versionRegexp)
+		if plugin.Marker != "" {
+			checkCoordinate(err, entry, "marker", plugin.Marker)
+		}
+	}
+
+	if deps.Environment != nil {
+		for tool, version := range deps.Environment.Versions() {
+			checkPattern(err, "environment", tool, version, versionRegexp)
+		}
+		if strings.IndexFunc(deps.Environment.GradleDistributionUrl, unicode.IsControl) >= 0 {
+			err.add("environment", "gradle-distribution-url", printable(deps.Environment.GradleDistributionUrl), "must not contain control characters")
+		}
+	}
+
+	for idx, edge := range deps.Edges {
+		entry := fmt.Sprintf("edges[%d]", idx)
+		if edge.From != "" {
+			checkCoordinate(err, entry, "from", edge.From)
+		}
+		checkCoordinate(err, entry, "to", edge.To)
+	}
+
+	for idx, artifact := range deps.Produces {
+		checkCoordinate(err, fmt.Sprintf("produces[%d]", idx), "artifact", artifact)
+	}
+
+	if len(err.Violations) > 0 {
+		return err
+	}
+	return nil
+}
+
+// ValidateProducers rejects produces entries linked with another repository, otherwise any uploader could relink
+// artifacts of other repositories in repository graph
+func ValidateProducers(repo string, produces []string, linked map[string]storage.ProducerDto) *ValidationError {
+	err := &ValidationError{StatusCode: http.StatusConflict, Status: "Artifacts are produced by another repository"}
+	for idx, artifact := range produces {
+		if producer, found := linked[artifact]; found && producer.Repo != repo {
+			err.add(fmt.Sprintf("produces[%d]", idx), "artifact", artifact, fmt.Sprintf("is produced by %s", producer.Repo))
+		}
+	}
+	if len(err.Violations) > 0 {
+		return err
+	}
+	return nil
+}
+
+func checkPattern(err *ValidationError, entry string, field string, value string, pattern *regexp.Regexp) {
+	if value == "" {
+		err.add(entry, field, value, "must not be empty")
+	} else if !pattern.MatchString(value) {
+		err.add(entry, field, printable(value), fmt.Sprintf("must match %s", pattern.String()))
+	}
+}
+
+// checkCoordinate checks group:name form
+func checkCoordinate(err *ValidationError, entry string, field string, value string) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 || !coordinateRegexp.MatchString(parts[0]) || !coordinateRegexp.MatchString(parts[1]) {
+		err.add(entry, field, printable(value), "must be group:name")
+	}
+}
+
+func checkKeyPart(value string) string {
+	switch {
+	case value == "":
+		return "must not be empty"
+	case len(value) > MaxPathLength:
+		return fmt.Sprintf("must not be longer than %d", MaxPathLength)
+	case strings.IndexFunc(value, unicode.IsControl) >= 0:
+		return "must not contain control characters"
+	case strings.Contains(value, ":"):
+		return "must not contain ':'"
+	}
+	return ""
+}
+
+// checkText allows free text like license names as found in POMs and file paths
+func checkText(value string) string {
+	switch {
+	case strings.TrimSpace(value) == "":
+		return "must not be empty"
+	case len(value) > MaxPathLength:
+		return fmt.Sprintf("must not be longer than %d", MaxPathLength)
+	case strings.IndexFunc(value, unicode.IsControl) >= 0:
+		return "must not contain control characters"
+	}
+	return ""
+}
+
+// checkLocation allows relative paths inside repository only
+func checkLocation(value string) string {
+	if msg := checkText(value); msg != "" {
+		return msg
+	}
+	if strings.HasPrefix(value, "/") || strings.Contains(value, "\\") || value == ".." || strings.HasPrefix(value, "../") || strings.Contains(value, "/../") {
+		return "must be relative path inside repository"
+	}
+	return ""
+}
+
+// printable keeps invalid value readable in response
+func printable(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return '?'
+		}
+		return r
+	}, value)
+	if len(value) > MaxPathLength {
+		value = value[:MaxPathLength]
+	}
+	return value
+}
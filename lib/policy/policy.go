@@ -0,0 +1,251 @@
+package policy
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gradle-serverless-dependencies-graph/lib/conflicts"
+	"gradle-serverless-dependencies-graph/lib/licenses"
+	"gradle-serverless-dependencies-graph/lib/maven"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"os"
+	"path"
+	"sort"
+)
+
+const (
+	ActionDeny  = "deny"
+	ActionAllow = "allow"
+)
+
+// Policy is set of rules, YAML or JSON:
+//
+//	enforce: true
+//	rules:
+//	  - id: log4shell
+//	    dependency: org.apache.logging.log4j:log4j-core
+//	    versions: "(,2.17.1)"
+//	  - id: no-snapshots-on-main
+//	    dependency: "*"
+//	    snapshot: true
+//	    refs: [main]
+//	  - id: forbidden-licenses
+//	    licenses: [GPL, AGPL]
+//	conflicts:
+//	  - id: guava-google-collections
+//	    disabled: true
+type Policy struct {
+	// Enforce rejects uploads with violations instead of reporting them
+	Enforce bool   `json:"enforce" yaml:"enforce"`
+	Rules   []Rule `json:"rules" yaml:"rules"`
+	// Conflicts are added to built-in conflict rules, see conflicts.Rules
+	Conflicts []conflicts.Rule `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// Rule matches dependencies by group:name glob, Maven version range, licenses, configurations and repo/ref globs,
+// allow rules are exceptions for deny rules. Licenses are SPDX ids, families (GPL) or licenses.Unknown, plugins never match them
+type Rule struct {
+	Id             string   `json:"id" yaml:"id"`
+	Description    string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Action         string   `json:"action,omitempty" yaml:"action,omitempty"`
+	Dependency     string   `json:"dependency" yaml:"dependency"`
+	Versions       string   `json:"versions,omitempty" yaml:"versions,omitempty"`
+	Snapshot       bool     `json:"snapshot,omitempty" yaml:"snapshot,omitempty"`
+	Licenses       []string `json:"licenses,omitempty" yaml:"licenses,omitempty"`
+	Configurations []string `json:"configurations,omitempty" yaml:"configurations,omitempty"`
+	Repos          []string `json:"repos,omitempty" yaml:"repos,omitempty"`
+	Refs           []string `json:"refs,omitempty" yaml:"refs,omitempty"`
+
+	versions *maven.Range
+}
+
+// Parse reads YAML or JSON policy and validates rules
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("malformed policy: %s", err)
+	}
+
+	ids := map[string]bool{}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Id == "" {
+			return nil, fmt.Errorf("rule #%d: id is required", i+1)
+		}
+		if ids[rule.Id] {
+			return nil, fmt.Errorf("rule %s: duplicate id", rule.Id)
+		}
+		ids[rule.Id] = true
+
+		if rule.Action == "" {
+			rule.Action = ActionDeny
+		}
+		if rule.Action != ActionDeny && rule.Action != ActionAllow {
+			return nil, fmt.Errorf("rule %s: action must be %s or %s", rule.Id, ActionDeny, ActionAllow)
+		}
+		if rule.Dependency == "" && len(rule.Licenses) > 0 {
+			rule.Dependency = "*"
+		}
+		if rule.Dependency == "" {
+			return nil, fmt.Errorf("rule %s: dependency is required, use * for any", rule.Id)
+		}
+		patterns := append([]string{rule.Dependency}, rule.Configurations...)
+		patterns = append(patterns, rule.Licenses...)
+		patterns = append(append(patterns, rule.Repos...), rule.Refs...)
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %s: malformed pattern %q", rule.Id, pattern)
+			}
+		}
+		if rule.Versions != "" {
+			r, err := maven.ParseRange(rule.Versions)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %s", rule.Id, err)
+			}
+			rule.versions = r
+		}
+	}
+	if err := conflicts.Validate(p.Conflicts); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Evaluate returns violations of deny rules for uploaded dependencies and plugins of repo/ref
+func (p *Policy) Evaluate(repo string, ref string, deps storage.DependenciesRest) []storage.ViolationDto {
+	var candidates []Candidate
+	for _, dep := range deps.Dependencies {
+		candidates = append(candidates, Candidate{
+			Dependency:     fmt.Sprintf("%s:%s", dep.Group, dep.Name),
+			Version:        dep.Version,
+			Configurations: dep.Configurations,
+			Licenses:       licenses.Of(dep.Licenses),
+		})
+	}
+	for _, plugin := range deps.Plugins {
+		candidates = append(candidates, Candidate{Dependency: plugin.Id, Version: plugin.Version})
+	}
+
+	seen := map[string]bool{}
+	var result []storage.ViolationDto
+	for _, c := range candidates {
+		if p.matchesAny(ActionAllow, repo, ref, c) != nil {
+			continue
+		}
+		for _, rule := range p.Rules {
+			if rule.Action != ActionDeny || !rule.Matches(repo, ref, c) {
+				continue
+			}
+			violation := storage.NewViolationDto(repo, ref, rule.Id, c.Dependency, c.Version)
+			if seen[violation.Key] {
+				continue
+			}
+			seen[violation.Key] = true
+			violation.Description = rule.Description
+			violation.Configurations = c.Configurations
+			if len(rule.Licenses) > 0 {
+				violation.Licenses = c.Licenses
+			}
+			result = append(result, violation)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func (p *Policy) matchesAny(action string, repo string, ref string, c Candidate) *Rule {
+	for i, rule := range p.Rules {
+		if rule.Action == action && rule.Matches(repo, ref, c) {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Candidate is uploaded dependency or plugin, plugins have no licenses
+type Candidate struct {
+	Dependency     string
+	Version        string
+	Configurations []string
+	Licenses       []string
+}
+
+func (r Rule) Matches(repo string, ref string, c Candidate) bool {
+	if !matchGlob(r.Dependency, c.Dependency) {
+		return false
+	}
+	if r.versions == nil && r.Versions != "" {
+		r.versions, _ = maven.ParseRange(r.Versions)
+	}
+	if r.versions != nil && !r.versions.Contains(c.Version) {
+		return false
+	}
+	if r.Snapshot && !maven.IsSnapshot(c.Version) {
+		return false
+	}
+	if len(r.Repos) > 0 && !matchAnyGlob(r.Repos, repo) {
+		return false
+	}
+	if len(r.Refs) > 0 && !matchAnyGlob(r.Refs, ref) {
+		return false
+	}
+	if len(r.Licenses) > 0 && !r.matchesLicenses(c.Licenses) {
+		return false
+	}
+	if len(r.Configurations) > 0 {
+		matched := false
+		for _, conf := range c.Configurations {
+			if matchAnyGlob(r.Configurations, conf) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchGlob works as path.Match, single * matches anything including refs with '/'
+func matchGlob(pattern string, value string) bool {
+	if pattern == "*" {
+		return true
+	}
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// matchesLicenses of deny rule requires license expression without allowed alternative,
+// other rules match expression having alternative made only of rule licenses
+func (r Rule) matchesLicenses(expressions []string) bool {
+	for _, expression := range expressions {
+		if r.Action == ActionDeny && licenses.Denied(r.Licenses, expression) {
+			return true
+		}
+		if r.Action != ActionDeny && licenses.Allowed(r.Licenses, expression) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads policy file, e.g. bundled with lambda or passed to CLI
+func Load(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
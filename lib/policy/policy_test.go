@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"reflect"
+	"testing"
+)
+
+const testPolicy = `
+enforce: true
+rules:
+  - id: log4shell
+    description: Log4Shell, upgrade to 2.17.1
+    dependency: org.apache.logging.log4j:log4j-core
+    versions: "(,2.17.1)"
+  - id: commons-collections-3
+    dependency: commons-collections:commons-collections
+    versions: "[3.0,4.0)"
+    configurations: ["*RuntimeClasspath"]
+  - id: no-snapshots-on-main
+    dependency: "*"
+    snapshot: true
+    refs: [main, release/*]
+  - id: forbidden-licenses
+    licenses: [GPL, AGPL]
+  - id: legacy-exception
+    action: allow
+    dependency: commons-collections:*
+    repos: [acme/legacy]
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Enforce || len(p.Rules) != 5 {
+		t.Fatalf("Wrong policy %+v", p)
+	}
+	if p.Rules[0].Action != ActionDeny || p.Rules[4].Action != ActionAllow {
+		t.Errorf("Wrong actions %s, %s", p.Rules[0].Action, p.Rules[4].Action)
+	}
+	if p.Rules[3].Dependency != "*" {
+		t.Errorf("License rule should match any dependency, got %s", p.Rules[3].Dependency)
+	}
+
+	if _, err := Parse([]byte(`{"rules": [{"id": "json", "dependency": "a:b", "versions": "[1.0,2.0)"}]}`)); err != nil {
+		t.Errorf("JSON policy is not parsed: %s", err)
+	}
+
+	malformed := []string{
+		`rules: [{dependency: "a:b"}]`,
+		`rules: [{id: x, dependency: "a:b"}, {id: x, dependency: "c:d"}]`,
+		`rules: [{id: x}]`,
+		`rules: [{id: x, dependency: "a:b", action: block}]`,
+		`rules: [{id: x, dependency: "a:b", versions: "[1.0"}]`,
+		`rules: [{id: x, dependency: "a:[b"}]`,
+		`conflicts: [{id: x, families: [{name: a, artifacts: ["a:b"]}]}]`,
+	}
+	for _, data := range malformed {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Expected error for %s", data)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := storage.DependenciesRest{
+		Dependencies: []storage.DependencyRest{
+			{Group: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1", Configurations: []string{"runtimeClasspath"}},
+			{Group: "org.apache.logging.log4j", Name: "log4j-api", Version: "2.14.1"},
+			{Group: "commons-collections", Name: "commons-collections", Version: "3.2.2", Configurations: []string{"releaseRuntimeClasspath"}},
+			{Group: "acme", Name: "core", Version: "1.0-SNAPSHOT"},
+			{Group: "acme", Name: "gpl-lib", Version: "1.0", Licenses: []string{"MIT", "GPL-3.0-only"}},
+			{Group: "acme", Name: "lgpl-lib", Version: "1.0", Licenses: []string{"LGPL-2.1-only"}},
+			{Group: "acme", Name: "dual-lib", Version: "1.0", Licenses: []string{"GPL-3.0-only OR MIT"}},
+		},
+		Plugins: []storage.PluginRest{{Id: "acme.conventions", Version: "2.0-SNAPSHOT"}},
+	}
+
+	keys := func(violations []storage.ViolationDto) []string {
+		var result []string
+		for _, v := range violations {
+			result = append(result, v.Key)
+		}
+		return result
+	}
+
+	expected := []string{
+		"commons-collections-3:commons-collections:commons-collections:3.2.2",
+		"forbidden-licenses:acme:gpl-lib:1.0",
+		"log4shell:org.apache.logging.log4j:log4j-core:2.14.1",
+		"no-snapshots-on-main:acme.conventions:2.0-SNAPSHOT",
+		"no-snapshots-on-main:acme:core:1.0-SNAPSHOT",
+	}
+	violations := p.Evaluate("acme/app", "release/1.x", deps)
+	if actual := keys(violations); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Wrong violations %v", actual)
+	}
+	if violations[2].Id != "acme/app:release/1.x" || violations[2].Description == "" {
+		t.Errorf("Wrong violation %+v", violations[2])
+	}
+	if !reflect.DeepEqual(violations[1].Licenses, []string{"MIT", "GPL-3.0-only"}) {
+		t.Errorf("License violation should keep licenses, got %+v", violations[1])
+	}
+
+	expected = []string{"forbidden-licenses:acme:gpl-lib:1.0", "log4shell:org.apache.logging.log4j:log4j-core:2.14.1"}
+	if actual := keys(p.Evaluate("acme/legacy", "feature/x", deps)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Wrong violations with allow rule and feature ref %v", actual)
+	}
+}
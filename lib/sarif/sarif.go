@@ -0,0 +1,228 @@
+package sarif
+
+import (
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/osv"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"strings"
+)
+
+const (
+	Version     = "2.1.0"
+	Schema      = "https://json.schemastore.org/sarif-2.1.0.json"
+	ContentType = "application/sarif+json"
+	ToolName    = "gradle-dependencies-graph"
+
+	// DefaultFile is reported for dependencies without known location, code scanning requires one
+	DefaultFile = "build.gradle"
+
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+type (
+	Log struct {
+		Version string `json:"version"`
+		Schema  string `json:"$schema"`
+		Runs    []Run  `json:"runs"`
+	}
+
+	Run struct {
+		Tool    Tool     `json:"tool"`
+		Results []Result `json:"results"`
+	}
+
+	Tool struct {
+		Driver Driver `json:"driver"`
+	}
+
+	Driver struct {
+		Name    string                `json:"name"`
+		Version string                `json:"version,omitempty"`
+		Rules   []ReportingDescriptor `json:"rules"`
+	}
+
+	ReportingDescriptor struct {
+		Id                   string                 `json:"id"`
+		Name                 string                 `json:"name,omitempty"`
+		ShortDescription     *Message               `json:"shortDescription,omitempty"`
+		FullDescription      *Message               `json:"fullDescription,omitempty"`
+		HelpUri              string                 `json:"helpUri,omitempty"`
+		DefaultConfiguration *Configuration         `json:"defaultConfiguration,omitempty"`
+		Properties           map[string]interface{} `json:"properties,omitempty"`
+	}
+
+	Configuration struct {
+		Level string `json:"level"`
+	}
+
+	Message struct {
+		Text string `json:"text"`
+	}
+
+	Result struct {
+		RuleId              string            `json:"ruleId"`
+		RuleIndex           int               `json:"ruleIndex"`
+		Level               string            `json:"level"`
+		Message             Message           `json:"message"`
+		Locations           []Location        `json:"locations"`
+		PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	}
+
+	Location struct {
+		PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+	}
+
+	PhysicalLocation struct {
+		ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+		Region           *Region          `json:"region,omitempty"`
+	}
+
+	ArtifactLocation struct {
+		Uri string `json:"uri"`
+	}
+
+	Region struct {
+		StartLine int `json:"startLine"`
+	}
+)
+
+// Report collects results of one repo/ref, rules are added once in order of first use
+type Report struct {
+	run       Run
+	rules     map[string]int
+	locations map[string]storage.StorageDto
+	// DefaultFile replaces unknown location
+	DefaultFile string
+}
+
+// NewReport uses stored items of repo/ref to find where dependencies are declared
+func NewReport(toolVersion string, items []storage.StorageDto) *Report {
+	report := &Report{
+		run: Run{
+			Tool: Tool{Driver: Driver{
+				Name:    ToolName,
+				Version: toolVersion,
+				Rules:   []ReportingDescriptor{},
+			}},
+			Results: []Result{},
+		},
+		rules:       map[string]int{},
+		locations:   map[string]storage.StorageDto{},
+		DefaultFile: DefaultFile,
+	}
+	for _, item := range items {
+		key := item.Dependency + ":" + item.Version
+		if known, ok := report.locations[key]; !ok || (known.File == "" && item.File != "") {
+			report.locations[key] = item
+		}
+	}
+	return report
+}
+
+// AddFindings reports vulnerable dependencies, rule per advisory
+func (r *Report) AddFindings(findings []osv.Finding) {
+	for _, finding := range findings {
+		level := SeverityLevel(finding.Severity)
+		index := r.rule(ReportingDescriptor{
+			Id:                   "osv/" + finding.Advisory,
+			Name:                 "VulnerableDependency",
+			ShortDescription:     &Message{Text: firstNonEmpty(finding.Summary, finding.Advisory)},
+			FullDescription:      &Message{Text: fmt.Sprintf("%s %s", finding.Advisory, strings.Join(finding.Aliases, " "))},
+			HelpUri:              "https://osv.dev/vulnerability/" + finding.Advisory,
+			DefaultConfiguration: &Configuration{Level: level},
+			Properties:           map[string]interface{}{"tags": []string{"security", "dependency"}, "security-severity": SecuritySeverity(finding.Severity)},
+		})
+		r.add(index, level, finding.Dependency, finding.Version,
+			fmt.Sprintf("%s:%s is affected by %s: %s", finding.Dependency, finding.Version, finding.Advisory, finding.Summary))
+	}
+}
+
+// AddViolations reports policy violations, violations of license rules are tagged as license findings
+func (r *Report) AddViolations(violations []storage.ViolationDto) {
+	for _, violation := range violations {
+		prefix, tag, message := "policy/", "policy", fmt.Sprintf("%s:%s violates policy rule %s", violation.Dependency, violation.Version, violation.Rule)
+		if len(violation.Licenses) > 0 {
+			prefix, tag = "license/", "license"
+			message = fmt.Sprintf("%s:%s has banned license %s (rule %s)", violation.Dependency, violation.Version, strings.Join(violation.Licenses, ", "), violation.Rule)
+		}
+		if violation.Description != "" {
+			message = message + ": " + violation.Description
+		}
+		index := r.rule(ReportingDescriptor{
+			Id:                   prefix + violation.Rule,
+			Name:                 "PolicyViolation",
+			ShortDescription:     &Message{Text: firstNonEmpty(violation.Description, "Policy rule "+violation.Rule)},
+			DefaultConfiguration: &Configuration{Level: LevelError},
+			Properties:           map[string]interface{}{"tags": []string{tag, "dependency"}},
+		})
+		r.add(index, LevelError, violation.Dependency, violation.Version, message)
+	}
+}
+
+func (r *Report) Log() Log {
+	return Log{Version: Version, Schema: Schema, Runs: []Run{r.run}}
+}
+
+func (r *Report) rule(descriptor ReportingDescriptor) int {
+	if index, ok := r.rules[descriptor.Id]; ok {
+		return index
+	}
+	r.rules[descriptor.Id] = len(r.run.Tool.Driver.Rules)
+	r.run.Tool.Driver.Rules = append(r.run.Tool.Driver.Rules, descriptor)
+	return r.rules[descriptor.Id]
+}
+
+func (r *Report) add(ruleIndex int, level string, dependency string, version string, message string) {
+	location := PhysicalLocation{ArtifactLocation: ArtifactLocation{Uri: r.DefaultFile}}
+	if item, ok := r.locations[dependency+":"+version]; ok && item.File != "" {
+		location.ArtifactLocation.Uri = item.File
+		if item.Line > 0 {
+			location.Region = &Region{StartLine: item.Line}
+		}
+	}
+	ruleId := r.run.Tool.Driver.Rules[ruleIndex].Id
+	r.run.Results = append(r.run.Results, Result{
+		RuleId:    ruleId,
+		RuleIndex: ruleIndex,
+		Level:     level,
+		Message:   Message{Text: message},
+		Locations: []Location{{PhysicalLocation: location}},
+		// Line changes with every lockfile update, so alerts are matched by rule and artifact
+		PartialFingerprints: map[string]string{"dependency/v1": fmt.Sprintf("%s:%s:%s", ruleId, dependency, version)},
+	})
+}
+
+// SeverityLevel maps GHSA severity to SARIF level
+func SeverityLevel(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "HIGH":
+		return LevelError
+	case "LOW":
+		return LevelNote
+	}
+	return LevelWarning
+}
+
+// SecuritySeverity is CVSS like score used by code scanning UIs to rank alerts
+func SecuritySeverity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return "9.5"
+	case "HIGH":
+		return "8.0"
+	case "LOW":
+		return "2.0"
+	}
+	return "5.5"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package sarif
+
+import (
+	"encoding/json"
+	"gradle-serverless-dependencies-graph/lib/osv"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	items := []storage.StorageDto{
+		{Dependency: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", File: "app/gradle.lockfile", Line: 12},
+		{Dependency: "acme:gpl-lib", Version: "1.0"},
+	}
+	report := NewReport("1.2.3", items)
+	report.AddFindings([]osv.Finding{
+		{Dependency: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Advisory: "GHSA-jfh8-c2jp-5v3q", Aliases: []string{"CVE-2021-44228"}, Severity: "CRITICAL", Summary: "Remote code injection in Log4j"},
+	})
+	report.AddViolations([]storage.ViolationDto{
+		{Rule: "log4shell", Dependency: "org.apache.logging.log4j:log4j-core", Version: "2.14.1"},
+		{Rule: "forbidden-licenses", Dependency: "acme:gpl-lib", Version: "1.0", Licenses: []string{"GPL-3.0-only"}},
+		{Rule: "forbidden-licenses", Dependency: "acme:other-gpl-lib", Version: "2.0", Licenses: []string{"AGPL-3.0-only"}},
+	})
+
+	log := report.Log()
+	if log.Version != Version || len(log.Runs) != 1 {
+		t.Fatalf("Wrong log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 3 || len(run.Results) != 4 {
+		t.Fatalf("Wrong rules %d or results %d", len(run.Tool.Driver.Rules), len(run.Results))
+	}
+
+	vulnerable := run.Results[0]
+	if vulnerable.RuleId != "osv/GHSA-jfh8-c2jp-5v3q" || vulnerable.Level != LevelError {
+		t.Errorf("Wrong vulnerability result %+v", vulnerable)
+	}
+	location := vulnerable.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.Uri != "app/gradle.lockfile" || location.Region == nil || location.Region.StartLine != 12 {
+		t.Errorf("Wrong location %+v", location)
+	}
+
+	licensed := run.Results[2]
+	if licensed.RuleId != "license/forbidden-licenses" || licensed.RuleIndex != 2 || run.Results[3].RuleIndex != 2 {
+		t.Errorf("Wrong license result %+v", licensed)
+	}
+	if uri := licensed.Locations[0].PhysicalLocation.ArtifactLocation.Uri; uri != DefaultFile {
+		t.Errorf("Unknown location should fall back to %s, got %s", DefaultFile, uri)
+	}
+
+	body, err := json.Marshal(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded["$schema"] != Schema {
+		t.Errorf("Wrong JSON %s", body)
+	}
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+const AliasPrefix = "-alias:"
+
+func (svc *Storage) ImportAdvisories(ctxId string, items []AdvisoryDto) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		insert, err := svc.putItem(*svc.Config.AdvisoriesTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ImportAdvisories", map[string]string{"id": item.Id})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "ImportAdvisories", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+// ListAdvisoriesByPackage returns advisories of group:name, or advisory ids for AliasPrefix+alias
+func (svc *Storage) ListAdvisoriesByPackage(ctxId string, pkg string) (*[]AdvisoryDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListAdvisoriesByPackage() called", ctxId),
+		zap.String("package", pkg),
+	)
+
+	var consistentRead = false
+	params := &dynamodb.QueryInput{
+		TableName:              svc.Config.AdvisoriesTableName,
+		ConsistentRead:         &consistentRead,
+		KeyConditionExpression: aws.String("#package = :package"),
+		ExpressionAttributeNames: map[string]string{
+			"#package": "Package",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":package": &types.AttributeValueMemberS{Value: pkg},
+		},
+	}
+	paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
+
+	result := []AdvisoryDto{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ListAdvisoriesByPackage",
+				map[string]string{
+					"package": pkg,
+				},
+				zap.String("package", pkg),
+			)
+		}
+
+		var advisoriesResp []AdvisoryDto
+		err = attributevalue.UnmarshalListOfMaps(page.Items, &advisoriesResp)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ListAdvisoriesByPackage",
+				map[string]string{
+					"package": pkg,
+				},
+				zap.String("package", pkg),
+			)
+		}
+		result = append(result, advisoriesResp...)
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListAdvisoriesByPackage() result", ctxId),
+		zap.String("package", pkg),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
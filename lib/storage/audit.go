@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AuditActionUpload        = "upload"
+	AuditActionTokenCreate   = "token-create"
+	AuditActionTokenRevoke   = "token-revoke"
+	AuditActionPolicyPut     = "policy-put"
+	AuditActionWebhookCreate = "webhook-create"
+	AuditActionWebhookDelete = "webhook-delete"
+
+	// Actions of gdg CLI working with DynamoDB directly
+	AuditActionImport        = "import"
+	AuditActionOsvImport     = "osv-import"
+	AuditActionLicenseImport = "license-import"
+
+	// AuditTimeLayout is fixed width UTC time, so Time strings sort and compare in time order
+	AuditTimeLayout = "2006-01-02T15:04:05.000000000Z"
+)
+
+// PutAudit stores audit entry of mutation, Id and Time are filled when empty
+func (svc *Storage) PutAudit(ctxId string, entry AuditDto) *StorageErrorRest {
+	if entry.Time == "" {
+		entry.Time = time.Now().UTC().Format(AuditTimeLayout)
+	}
+	if entry.Id == "" {
+		entry.Id = fmt.Sprintf("%s:%s", entry.Time, entry.RequestId)
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s PutAudit() called", ctxId),
+		zap.Reflect("entry", entry),
+	)
+
+	item, err := attributevalue.MarshalMap(entry)
+	if err == nil {
+		_, err = svc.DynamoDb.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName: svc.Config.AuditTableName,
+			Item:      item,
+		})
+	}
+	if err != nil {
+		return svc.handleError(ctxId, err, "PutAudit",
+			map[string]string{
+				"principal": entry.Principal,
+				"action":    entry.Action,
+			},
+			zap.Reflect("entry", entry),
+		)
+	}
+
+	return nil
+}
+
+// ListAudit queries Principal or Repo index, time only filter scans whole table. From and To are compared
+// with Time as strings, so they are formatted with AuditTimeLayout
+func (svc *Storage) ListAudit(ctxId string, filter AuditFilter) (*[]AuditDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListAudit() called", ctxId),
+		zap.Reflect("filter", filter),
+	)
+
+	values := map[string]types.AttributeValue{}
+	names := map[string]string{}
+	var conditions []string
+	var index, keyCondition string
+
+	switch {
+	case filter.Principal != "":
+		index, keyCondition = "Principal", "#principal = :principal"
+		names["#principal"] = "Principal"
+		values[":principal"] = &types.AttributeValueMemberS{Value: filter.Principal}
+		if filter.Repo != "" {
+			conditions = append(conditions, "#repo = :repo")
+			names["#repo"] = "Repo"
+			values[":repo"] = &types.AttributeValueMemberS{Value: filter.Repo}
+		}
+	case filter.Repo != "":
+		index, keyCondition = "Repo", "#repo = :repo"
+		names["#repo"] = "Repo"
+		values[":repo"] = &types.AttributeValueMemberS{Value: filter.Repo}
+	}
+
+	var timeCondition string
+	if filter.From != "" || filter.To != "" {
+		names["#time"] = "Time"
+		from, to := filter.From, filter.To
+		if from == "" {
+			from = "0"
+		}
+		if to == "" {
+			to = "9"
+		}
+		timeCondition = "#time BETWEEN :from AND :to"
+		values[":from"] = &types.AttributeValueMemberS{Value: from}
+		values[":to"] = &types.AttributeValueMemberS{Value: to}
+	}
+
+	var result []AuditDto
+	appendPage := func(items []map[string]types.AttributeValue) error {
+		var entries []AuditDto
+		if err := attributevalue.UnmarshalListOfMaps(items, &entries); err != nil {
+			return err
+		}
+		result = append(result, entries...)
+		return nil
+	}
+
+	if index != "" {
+		if timeCondition != "" {
+			keyCondition = keyCondition + " and " + timeCondition
+		}
+		params := &dynamodb.QueryInput{
+			TableName:                 svc.Config.AuditTableName,
+			IndexName:                 aws.String(index),
+			KeyConditionExpression:    aws.String(keyCondition),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ScanIndexForward:          aws.Bool(false),
+		}
+		if len(conditions) > 0 {
+			params.FilterExpression = aws.String(strings.Join(conditions, " and "))
+		}
+		paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err == nil {
+				err = appendPage(page.Items)
+			}
+			if err != nil {
+				return nil, svc.handleError(ctxId, err, "ListAudit", map[string]string{}, zap.Reflect("filter", filter))
+			}
+		}
+	} else {
+		params := &dynamodb.ScanInput{
+			TableName: svc.Config.AuditTableName,
+		}
+		if timeCondition != "" {
+			params.FilterExpression = aws.String(timeCondition)
+			params.ExpressionAttributeNames = names
+			params.ExpressionAttributeValues = values
+		}
+		paginator := dynamodb.NewScanPaginator(svc.DynamoDb, params)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err == nil {
+				err = appendPage(page.Items)
+			}
+			if err != nil {
+				return nil, svc.handleError(ctxId, err, "ListAudit", map[string]string{}, zap.Reflect("filter", filter))
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time > result[j].Time
+	})
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListAudit() result", ctxId),
+		zap.Reflect("filter", filter),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
@@ -64,10 +64,11 @@ func (svc *Storage) ListDependenciesByParent(ctxId string, parent *string) (*[]D
 	return &result, nil
 }
 
-func (svc *Storage) ListDependenciesByRepo(ctxId string, repo string, ref string) (*[]StorageDto, *StorageErrorRest) {
+func (svc *Storage) ListDependenciesByRepo(ctxId string, repo string, ref string, filter *StorageFilter) (*[]StorageDto, *StorageErrorRest) {
 	svc.Logger.Debug(fmt.Sprintf("%s ListDependenciesByRepo() called", ctxId),
 		zap.String("repo", repo),
 		zap.String("ref", ref),
+		zap.Reflect("filter", filter),
 	)
 
 	var consistentRead = false
@@ -86,6 +87,7 @@ func (svc *Storage) ListDependenciesByRepo(ctxId string, repo string, ref string
 		},
 		Select: types.SelectAllAttributes,
 	}
+	filter.apply(params)
 	paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
 
 	var result []StorageDto
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"strings"
+)
+
+const EnvironmentPrefix = "-environment:"
+
+const (
+	ToolGradle = "gradle"
+	ToolJava   = "java"
+	ToolKotlin = "kotlin"
+)
+
+var EnvironmentTools = []string{ToolGradle, ToolJava, ToolKotlin}
+
+func (env EnvironmentRest) Versions() map[string]string {
+	result := make(map[string]string)
+
+	gradle := env.Gradle
+	if gradle == "" {
+		gradle = helpers.GradleVersionFromDistributionUrl(env.GradleDistributionUrl)
+	}
+	if gradle != "" {
+		result[ToolGradle] = gradle
+	}
+	if env.JavaToolchain != "" {
+		result[ToolJava] = env.JavaToolchain
+	}
+	if env.Kotlin != "" {
+		result[ToolKotlin] = env.Kotlin
+	}
+
+	return result
+}
+
+func (dto StorageDto) Tool() string {
+	return strings.TrimPrefix(dto.Dependency, EnvironmentPrefix)
+}
+
+func (svc *Storage) ListEnvironmentInventory(ctxId string) (*[]EnvironmentInventoryDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListEnvironmentInventory() called", ctxId))
+
+	var result []EnvironmentInventoryDto
+	for _, tool := range EnvironmentTools {
+		items, err := svc.ListRepositoriesByDependency(ctxId, EnvironmentPrefix+tool, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		inventory := EnvironmentInventoryDto{
+			Tool:     tool,
+			Versions: make(map[string][]StorageDto),
+		}
+		for _, item := range *items {
+			inventory.Versions[item.Version] = append(inventory.Versions[item.Version], item)
+		}
+		result = append(result, inventory)
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListEnvironmentInventory() result", ctxId),
+		zap.Reflect("result", &result),
+	)
+
+	return &result, nil
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+func (svc *Storage) ScanStorage(ctxId string, fn func(item StorageDto) error) *StorageErrorRest {
+	return svc.scan(ctxId, "ScanStorage", svc.Config.StorageTableName, func(item map[string]types.AttributeValue) error {
+		var dto StorageDto
+		if err := attributevalue.UnmarshalMap(item, &dto); err != nil {
+			return err
+		}
+		return fn(dto)
+	})
+}
+
+func (svc *Storage) ScanDependencies(ctxId string, fn func(item DependencyDto) error) *StorageErrorRest {
+	return svc.scan(ctxId, "ScanDependencies", svc.Config.DependenciesTableName, func(item map[string]types.AttributeValue) error {
+		var dto DependencyDto
+		if err := attributevalue.UnmarshalMap(item, &dto); err != nil {
+			return err
+		}
+		return fn(dto)
+	})
+}
+
+func (svc *Storage) ScanRepositories(ctxId string, fn func(item RepositoryDto) error) *StorageErrorRest {
+	return svc.scan(ctxId, "ScanRepositories", svc.Config.RepositoriesTableName, func(item map[string]types.AttributeValue) error {
+		var dto RepositoryDto
+		if err := attributevalue.UnmarshalMap(item, &dto); err != nil {
+			return err
+		}
+		return fn(dto)
+	})
+}
+
+// ScanItems walks raw items of any configured table, for tables exported without own model
+func (svc *Storage) ScanItems(ctxId string, table string, fn func(item map[string]types.AttributeValue) error) *StorageErrorRest {
+	return svc.scan(ctxId, "ScanItems", &table, fn)
+}
+
+// scan walks whole table page by page, so callers can stream items without keeping them in memory
+func (svc *Storage) scan(ctxId string, method string, table *string, fn func(item map[string]types.AttributeValue) error) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s %s() called", ctxId, method),
+		zap.Stringp("table", table),
+	)
+
+	var consistentRead = false
+	params := &dynamodb.ScanInput{
+		TableName:      table,
+		ConsistentRead: &consistentRead,
+	}
+	paginator := dynamodb.NewScanPaginator(svc.DynamoDb, params)
+
+	count := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return svc.handleError(ctxId, err, method,
+				map[string]string{},
+				zap.Stringp("table", table),
+			)
+		}
+
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return svc.handleError(ctxId, err, method,
+					map[string]string{},
+					zap.Stringp("table", table),
+				)
+			}
+			count++
+		}
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s %s() result", ctxId, method),
+		zap.Stringp("table", table),
+		zap.Int("count", count),
+	)
+
+	return nil
+}
+
+// Key returns storage table hash key, restoring it for items exported without one
+func (dto StorageDto) Key() string {
+	if dto.Id != "" {
+		return dto.Id
+	}
+	key := fmt.Sprintf("%s:%s:%s", dto.Repo, dto.Ref, dto.Dependency)
+	if dto.Variant != "" {
+		key = fmt.Sprintf("%s@%s", key, dto.Variant)
+	}
+	return key
+}
+
+// ImportStorage puts exported items back. Items are overwritten by key, so import can be safely repeated
+func (svc *Storage) ImportStorage(ctxId string, items []StorageDto) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		item.Id = item.Key()
+		insert, err := svc.putItem(*svc.Config.StorageTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ImportStorage", map[string]string{"id": item.Id})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "ImportStorage", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+func (svc *Storage) ImportDependencies(ctxId string, items []DependencyDto) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		insert, err := svc.putItem(*svc.Config.DependenciesTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ImportDependencies", map[string]string{"id": item.Parent})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "ImportDependencies", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+func (svc *Storage) ImportRepositories(ctxId string, items []RepositoryDto) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		insert, err := svc.putItem(*svc.Config.RepositoriesTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ImportRepositories", map[string]string{"repo": item.Parent})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "ImportRepositories", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+// ImportItems puts raw items exported by ScanItems back, items are overwritten by table key
+func (svc *Storage) ImportItems(ctxId string, table string, items []map[string]types.AttributeValue) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		batch = append(batch, InsertItem{
+			Table: table,
+			Item: types.WriteRequest{
+				PutRequest: &types.PutRequest{
+					Item: item,
+				},
+			},
+		})
+	}
+	return svc.batchWrite(ctxId, "ImportItems", batch, map[string]string{}, zap.String("table", table), zap.Int("count", len(items)))
+}
+
+func (svc *Storage) putItem(table string, item interface{}) (InsertItem, error) {
+	attrs, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return InsertItem{}, err
+	}
+	return InsertItem{
+		Table: table,
+		Item: types.WriteRequest{
+			PutRequest: &types.PutRequest{
+				Item: attrs,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+	"time"
+)
+
+// PutFreshness stores snapshots, snapshot of the same repo/ref and date is replaced
+func (svc *Storage) PutFreshness(ctxId string, items []FreshnessDto) (*UpsertResultRest, *StorageErrorRest) {
+	updated := time.Now().UTC().Format(time.RFC3339)
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		item.Updated = updated
+		insert, err := svc.putItem(*svc.Config.FreshnessTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "PutFreshness", map[string]string{"repo": item.Repo, "ref": item.Ref})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "PutFreshness", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+// ListFreshness returns snapshots of repo/ref ordered by date, or all snapshots in no particular order when repo is nil
+func (svc *Storage) ListFreshness(ctxId string, repo *string, ref *string) (*[]FreshnessDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListFreshness() called", ctxId),
+		zap.Stringp("repo", repo),
+		zap.Stringp("ref", ref),
+	)
+
+	result := []FreshnessDto{}
+	appendPage := func(items []map[string]types.AttributeValue) error {
+		var snapshots []FreshnessDto
+		if err := attributevalue.UnmarshalListOfMaps(items, &snapshots); err != nil {
+			return err
+		}
+		result = append(result, snapshots...)
+		return nil
+	}
+
+	if repo != nil && ref != nil {
+		params := &dynamodb.QueryInput{
+			TableName:              svc.Config.FreshnessTableName,
+			KeyConditionExpression: aws.String("Id = :id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":id": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s:%s", *repo, *ref)},
+			},
+		}
+		paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err == nil {
+				err = appendPage(page.Items)
+			}
+			if err != nil {
+				return nil, svc.handleError(ctxId, err, "ListFreshness", map[string]string{"repo": *repo, "ref": *ref})
+			}
+		}
+	} else {
+		errScan := svc.scan(ctxId, "ListFreshness", svc.Config.FreshnessTableName, func(item map[string]types.AttributeValue) error {
+			return appendPage([]map[string]types.AttributeValue{item})
+		})
+		if errScan != nil {
+			return nil, errScan
+		}
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListFreshness() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
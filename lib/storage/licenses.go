@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+const LicenseAnyVersion = "*"
+
+// VersionKey identifies dependency version in licenses and metadata tables, version could be * for any
+func VersionKey(dependency string, version string) string {
+	return fmt.Sprintf("%s:%s", dependency, version)
+}
+
+func (svc *Storage) ImportLicenses(ctxId string, items []LicenseDto) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		insert, err := svc.putItem(*svc.Config.LicensesTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ImportLicenses", map[string]string{"dependency": item.Dependency})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "ImportLicenses", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+// GetLicenses returns mapped licenses of dependency/version pairs by VersionKey, missing pairs are skipped
+func (svc *Storage) GetLicenses(ctxId string, keys []LicenseDto) (map[string]LicenseDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s GetLicenses() called", ctxId),
+		zap.Int("count", len(keys)),
+	)
+
+	var pending []map[string]types.AttributeValue
+	for _, key := range keys {
+		pending = append(pending, dependencyVersionKey(key.Dependency, key.Version))
+	}
+	items, errGet := svc.batchGet(ctxId, "GetLicenses", *svc.Config.LicensesTableName, pending)
+	if errGet != nil {
+		return nil, errGet
+	}
+
+	var licenses []LicenseDto
+	if err := attributevalue.UnmarshalListOfMaps(items, &licenses); err != nil {
+		return nil, svc.handleError(ctxId, err, "GetLicenses", map[string]string{})
+	}
+	result := make(map[string]LicenseDto, len(licenses))
+	for _, item := range licenses {
+		result[VersionKey(item.Dependency, item.Version)] = item
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s GetLicenses() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return result, nil
+}
+
+func dependencyVersionKey(dependency string, version string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"Dependency": &types.AttributeValueMemberS{Value: dependency},
+		"Version":    &types.AttributeValueMemberS{Value: version},
+	}
+}
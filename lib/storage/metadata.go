@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+const MetadataAnyVersion = "*"
+
+func (svc *Storage) PutMetadata(ctxId string, items []MetadataDto) (*UpsertResultRest, *StorageErrorRest) {
+	batch := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		insert, err := svc.putItem(*svc.Config.MetadataTableName, item)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "PutMetadata", map[string]string{"dependency": item.Dependency})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "PutMetadata", batch, map[string]string{}, zap.Int("count", len(items)))
+}
+
+// GetMetadata returns cached metadata of dependency/version pairs by VersionKey, expired items are returned too
+func (svc *Storage) GetMetadata(ctxId string, keys []MetadataDto) (map[string]MetadataDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s GetMetadata() called", ctxId),
+		zap.Int("count", len(keys)),
+	)
+
+	var pending []map[string]types.AttributeValue
+	for _, key := range keys {
+		pending = append(pending, dependencyVersionKey(key.Dependency, key.Version))
+	}
+	items, errGet := svc.batchGet(ctxId, "GetMetadata", *svc.Config.MetadataTableName, pending)
+	if errGet != nil {
+		return nil, errGet
+	}
+
+	var metadata []MetadataDto
+	if err := attributevalue.UnmarshalListOfMaps(items, &metadata); err != nil {
+		return nil, svc.handleError(ctxId, err, "GetMetadata", map[string]string{})
+	}
+	result := make(map[string]MetadataDto, len(metadata))
+	for _, item := range metadata {
+		result[VersionKey(item.Dependency, item.Version)] = item
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s GetMetadata() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return result, nil
+}
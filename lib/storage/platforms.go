@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+)
+
+const (
+	PlatformRegular  = "platform"
+	PlatformEnforced = "enforced-platform"
+)
+
+// Overrides reports whether dependency version differs from the one recommended by its platform
+func (dto StorageDto) Overrides() bool {
+	return dto.ManagedBy != "" && dto.ManagedVersion != "" && dto.ManagedVersion != dto.Version
+}
+
+func (svc *Storage) ListPlatformUsage(ctxId string, platform string, filter *StorageFilter) (*[]PlatformUsageDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListPlatformUsage() called", ctxId),
+		zap.String("platform", platform),
+		zap.Reflect("filter", filter),
+	)
+
+	items, err := svc.ListRepositoriesByDependency(ctxId, platform, nil, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variants repeat platform item of the same repo/ref, dependencies of repo/ref are loaded once for all of them
+	var result []PlatformUsageDto
+	seen := map[string]bool{}
+	loaded := map[string][]StorageDto{}
+	for _, item := range *items {
+		if item.Kind != KindPlatform {
+			continue
+		}
+		repoRef := fmt.Sprintf("%s/%s", item.Repo, item.Ref)
+		key := fmt.Sprintf("%s:%s:%s", repoRef, item.Version, item.Platform)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		deps, found := loaded[repoRef]
+		if !found {
+			stored, err := svc.ListDependenciesByRepo(ctxId, item.Repo, item.Ref, filter)
+			if err != nil {
+				return nil, err
+			}
+			deps = *stored
+			loaded[repoRef] = deps
+		}
+
+		usage := PlatformUsageDto{
+			Repo:     item.Repo,
+			Ref:      item.Ref,
+			Version:  item.Version,
+			Platform: item.Platform,
+		}
+		for _, dep := range deps {
+			if dep.ManagedBy != platform {
+				continue
+			}
+			usage.Managed = append(usage.Managed, dep)
+			if dep.Overrides() {
+				usage.Overrides = append(usage.Overrides, dep)
+			}
+		}
+		result = append(result, usage)
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListPlatformUsage() result", ctxId),
+		zap.String("platform", platform),
+		zap.Reflect("result", &result),
+	)
+
+	return &result, nil
+}
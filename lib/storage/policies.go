@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+	"time"
+)
+
+const DefaultPolicyId = "default"
+
+func NewViolationDto(repo string, ref string, rule string, dependency string, version string) ViolationDto {
+	return ViolationDto{
+		Id:         fmt.Sprintf("%s:%s", repo, ref),
+		Key:        fmt.Sprintf("%s:%s:%s", rule, dependency, version),
+		Repo:       repo,
+		Ref:        ref,
+		Rule:       rule,
+		Dependency: dependency,
+		Version:    version,
+	}
+}
+
+func (svc *Storage) PutPolicy(ctxId string, policy PolicyDto) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s PutPolicy() called", ctxId),
+		zap.String("id", policy.Id),
+	)
+
+	item, err := attributevalue.MarshalMap(policy)
+	if err == nil {
+		_, err = svc.DynamoDb.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName: svc.Config.PoliciesTableName,
+			Item:      item,
+		})
+	}
+	if err != nil {
+		return svc.handleError(ctxId, err, "PutPolicy",
+			map[string]string{
+				"id": policy.Id,
+			},
+			zap.String("id", policy.Id),
+		)
+	}
+	return nil
+}
+
+func (svc *Storage) GetPolicy(ctxId string, id string) (*PolicyDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s GetPolicy() called", ctxId),
+		zap.String("id", id),
+	)
+
+	resp, err := svc.DynamoDb.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: svc.Config.PoliciesTableName,
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, svc.handleError(ctxId, err, "GetPolicy",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+	if resp.Item == nil {
+		return nil, &StorageErrorRest{
+			Message: fmt.Sprintf("Error #%d Data Not Found", ErrObjectNotFound),
+			Code:    ErrObjectNotFound,
+			Id:      id,
+		}
+	}
+
+	var policy PolicyDto
+	if err := attributevalue.UnmarshalMap(resp.Item, &policy); err != nil {
+		return nil, svc.handleError(ctxId, err, "GetPolicy",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+	return &policy, nil
+}
+
+// ReplaceViolations stores current violations of repo/ref and removes fixed ones
+func (svc *Storage) ReplaceViolations(ctxId string, repo string, ref string, violations []ViolationDto) (*UpsertResultRest, *StorageErrorRest) {
+	existing, errList := svc.ListViolations(ctxId, &repo, &ref)
+	if errList != nil {
+		return nil, errList
+	}
+
+	keys := map[string]string{"repo": repo, "ref": ref}
+	updated := time.Now().UTC().Format(time.RFC3339)
+	current := make(map[string]bool, len(violations))
+	batch := make([]InsertItem, 0, len(violations)+len(*existing))
+	for _, violation := range violations {
+		violation.Updated = updated
+		current[violation.Key] = true
+		insert, err := svc.putItem(*svc.Config.ViolationsTableName, violation)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ReplaceViolations", keys)
+		}
+		batch = append(batch, insert)
+	}
+	for _, violation := range *existing {
+		if current[violation.Key] {
+			continue
+		}
+		batch = append(batch, InsertItem{
+			Table: *svc.Config.ViolationsTableName,
+			Item: types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{
+					Key: map[string]types.AttributeValue{
+						"Id":  &types.AttributeValueMemberS{Value: violation.Id},
+						"Key": &types.AttributeValueMemberS{Value: violation.Key},
+					},
+				},
+			},
+		})
+	}
+
+	return svc.batchWrite(ctxId, "ReplaceViolations", batch, keys, zap.String("repo", repo), zap.String("ref", ref))
+}
+
+// ListViolations returns violations of repo/ref, or all violations when repo is nil
+func (svc *Storage) ListViolations(ctxId string, repo *string, ref *string) (*[]ViolationDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListViolations() called", ctxId),
+		zap.Stringp("repo", repo),
+		zap.Stringp("ref", ref),
+	)
+
+	result := []ViolationDto{}
+	appendPage := func(items []map[string]types.AttributeValue) error {
+		var violations []ViolationDto
+		if err := attributevalue.UnmarshalListOfMaps(items, &violations); err != nil {
+			return err
+		}
+		result = append(result, violations...)
+		return nil
+	}
+
+	if repo != nil && ref != nil {
+		params := &dynamodb.QueryInput{
+			TableName:              svc.Config.ViolationsTableName,
+			KeyConditionExpression: aws.String("Id = :id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":id": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s:%s", *repo, *ref)},
+			},
+		}
+		paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err == nil {
+				err = appendPage(page.Items)
+			}
+			if err != nil {
+				return nil, svc.handleError(ctxId, err, "ListViolations", map[string]string{"repo": *repo, "ref": *ref})
+			}
+		}
+	} else {
+		errScan := svc.scan(ctxId, "ListViolations", svc.Config.ViolationsTableName, func(item map[string]types.AttributeValue) error {
+			return appendPage([]map[string]types.AttributeValue{item})
+		})
+		if errScan != nil {
+			return nil, errScan
+		}
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListViolations() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+	"time"
+)
+
+// PutProducers links artifacts with repo, callers check with GetProducers that artifacts are not linked to another repo.
+// Artifact moved to another repository is relinked after its item is deleted from producers table
+func (svc *Storage) PutProducers(ctxId string, repo string, ref string, artifacts []string) (*UpsertResultRest, *StorageErrorRest) {
+	keys := map[string]string{"repo": repo, "ref": ref}
+	updated := time.Now().UTC().Format(time.RFC3339)
+	batch := make([]InsertItem, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		insert, err := svc.putItem(*svc.Config.ProducersTableName, ProducerDto{Artifact: artifact, Repo: repo, Ref: ref, Updated: updated})
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "PutProducers", keys)
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "PutProducers", batch, keys, zap.String("repo", repo), zap.String("ref", ref))
+}
+
+// GetProducers returns repositories already linked with artifacts by Artifact, unknown artifacts are missing
+func (svc *Storage) GetProducers(ctxId string, artifacts []string) (map[string]ProducerDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s GetProducers() called", ctxId),
+		zap.Int("count", len(artifacts)),
+	)
+
+	var pending []map[string]types.AttributeValue
+	for _, artifact := range artifacts {
+		pending = append(pending, map[string]types.AttributeValue{"Artifact": &types.AttributeValueMemberS{Value: artifact}})
+	}
+	items, errGet := svc.batchGet(ctxId, "GetProducers", *svc.Config.ProducersTableName, pending)
+	if errGet != nil {
+		return nil, errGet
+	}
+
+	var producers []ProducerDto
+	if err := attributevalue.UnmarshalListOfMaps(items, &producers); err != nil {
+		return nil, svc.handleError(ctxId, err, "GetProducers", map[string]string{})
+	}
+	result := make(map[string]ProducerDto, len(producers))
+	for _, producer := range producers {
+		result[producer.Artifact] = producer
+	}
+	return result, nil
+}
+
+func (svc *Storage) ListProducers(ctxId string) (*[]ProducerDto, *StorageErrorRest) {
+	result := []ProducerDto{}
+	errScan := svc.scan(ctxId, "ListProducers", svc.Config.ProducersTableName, func(item map[string]types.AttributeValue) error {
+		var producer ProducerDto
+		if err := attributevalue.UnmarshalMap(item, &producer); err != nil {
+			return err
+		}
+		result = append(result, producer)
+		return nil
+	})
+	if errScan != nil {
+		return nil, errScan
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListProducers() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
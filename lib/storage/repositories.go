@@ -63,3 +63,74 @@ func (svc *Storage) ListRepositoriesByParent(ctxId string, parent *string) (*[]R
 
 	return &result, nil
 }
+
+func (svc *Storage) ListRepositoriesByDependency(ctxId string, dependency string, version *string, filter *StorageFilter) (*[]StorageDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListRepositoriesByDependency() called", ctxId),
+		zap.String("dependency", dependency),
+		zap.Stringp("version", version),
+		zap.Reflect("filter", filter),
+	)
+
+	keyCondition := "#dep = :dep"
+	values := map[string]types.AttributeValue{
+		":dep": &types.AttributeValueMemberS{Value: dependency},
+	}
+	names := map[string]string{
+		"#dep": "Dependency",
+	}
+	if version != nil {
+		keyCondition = "#dep = :dep and #version = :version"
+		values[":version"] = &types.AttributeValueMemberS{Value: *version}
+		names["#version"] = "Version"
+	}
+
+	var consistentRead = false
+	params := &dynamodb.QueryInput{
+		TableName:                 svc.Config.StorageTableName,
+		IndexName:                 ptr.String("Dependency"),
+		ConsistentRead:            &consistentRead,
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeValues: values,
+		ExpressionAttributeNames:  names,
+		Select:                    types.SelectAllAttributes,
+	}
+	filter.apply(params)
+	paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
+
+	var result []StorageDto
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ListRepositoriesByDependency",
+				map[string]string{
+					"id":      dependency,
+					"version": ptr.ToString(version),
+				},
+				zap.String("dependency", dependency),
+				zap.Stringp("version", version),
+			)
+		}
+
+		var repsResp []StorageDto
+		err = attributevalue.UnmarshalListOfMaps(page.Items, &repsResp)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ListRepositoriesByDependency",
+				map[string]string{
+					"id":      dependency,
+					"version": ptr.ToString(version),
+				},
+				zap.String("dependency", dependency),
+				zap.Stringp("version", version),
+			)
+		}
+		result = append(result, repsResp...)
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListRepositoriesByDependency() result", ctxId),
+		zap.String("dependency", dependency),
+		zap.Stringp("version", version),
+		zap.Reflect("result", &result),
+	)
+
+	return &result, nil
+}
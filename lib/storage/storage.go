@@ -7,9 +7,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/ptr"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"gradle-serverless-dependencies-graph/lib/helpers"
+	"strconv"
 	"time"
 )
 
@@ -23,6 +25,17 @@ type StorageConfig struct {
 	DependenciesTableName *string
 	RepositoriesTableName *string
 	StorageTableName      *string
+	TokensTableName       *string
+	AuditTableName        *string
+	AdvisoriesTableName   *string
+	PoliciesTableName     *string
+	ViolationsTableName   *string
+	LicensesTableName     *string
+	MetadataTableName     *string
+	FreshnessTableName    *string
+	ProducersTableName    *string
+	WebhooksTableName     *string
+	DeliveriesTableName   *string
 }
 
 type InsertItem struct {
@@ -35,7 +48,18 @@ const (
 	ErrObjectNotFound
 )
 
-const RootParent = "-"
+const (
+	RootParent     = "-"
+	PluginParent   = "-plugin"
+	PlatformParent = "-platform"
+)
+
+const (
+	KindLibrary     = "library"
+	KindPlugin      = "plugin"
+	KindEnvironment = "environment"
+	KindPlatform    = "platform"
+)
 
 func (s StorageErrorRest) Error() string {
 	panic(s.Message)
@@ -54,6 +78,17 @@ func NewStorage(cfg StorageConfig, logger *zap.Logger) (*Storage, error) {
 			DependenciesTableName: cfg.DependenciesTableName,
 			RepositoriesTableName: cfg.RepositoriesTableName,
 			StorageTableName:      cfg.StorageTableName,
+			TokensTableName:       cfg.TokensTableName,
+			AuditTableName:        cfg.AuditTableName,
+			AdvisoriesTableName:   cfg.AdvisoriesTableName,
+			PoliciesTableName:     cfg.PoliciesTableName,
+			ViolationsTableName:   cfg.ViolationsTableName,
+			LicensesTableName:     cfg.LicensesTableName,
+			MetadataTableName:     cfg.MetadataTableName,
+			FreshnessTableName:    cfg.FreshnessTableName,
+			ProducersTableName:    cfg.ProducersTableName,
+			WebhooksTableName:     cfg.WebhooksTableName,
+			DeliveriesTableName:   cfg.DeliveriesTableName,
 		},
 		DynamoDb: clientDynamoDb,
 		Logger:   logger,
@@ -71,13 +106,95 @@ func (svc *Storage) UpsertRepositoryInfo(ctxId string, repo string, ref string,
 
 	var insertBatch []InsertItem
 
+	// Collect edges: which dependencies requested given one and whether it is requested by project directly
+	parents := make(map[string][]string)
+	direct := make(map[string]bool)
+	for _, edge := range deps.Edges {
+		if edge.From == "" {
+			direct[edge.To] = true
+		} else {
+			parents[edge.To] = append(parents[edge.To], edge.From)
+		}
+	}
+
 	// Add data to storage. Details with dependencies and versions per repo/ref
-	var groupsToInsert map[string]bool
+	groupsToInsert := make(map[string]bool)
 	for _, dep := range deps.Dependencies {
 		Id := fmt.Sprintf("%s:%s:%s:%s", repo, ref, dep.Group, dep.Name)
 		Dep := fmt.Sprintf("%s:%s", dep.Group, dep.Name)
 
+		// Android variants resolve own dependency sets, so every variant gets own item
+		var variant string
+		if dep.Variant != nil {
+			variant = dep.Variant.Name()
+		}
+		if variant != "" {
+			Id = fmt.Sprintf("%s@%s", Id, variant)
+		}
+
 		groupsToInsert[dep.Group] = true
+
+		item := map[string]types.AttributeValue{
+			"Id":         &types.AttributeValueMemberS{Value: Id},
+			"Dependency": &types.AttributeValueMemberS{Value: Dep},
+			"Version":    &types.AttributeValueMemberS{Value: dep.Version},
+			"Repo":       &types.AttributeValueMemberS{Value: repo},
+			"Ref":        &types.AttributeValueMemberS{Value: ref},
+			"Kind":       &types.AttributeValueMemberS{Value: KindLibrary},
+			"Updated":    &types.AttributeValueMemberS{Value: updated},
+		}
+		if dep.Platform != "" {
+			// BOM imported via platform() or enforcedPlatform()
+			item["Kind"] = &types.AttributeValueMemberS{Value: KindPlatform}
+			item["Platform"] = &types.AttributeValueMemberS{Value: dep.Platform}
+			insertBatch = append(insertBatch,
+				// Add info to dependencies table: platform root -> group:name
+				InsertItem{
+					Table: *svc.Config.DependenciesTableName,
+					Item: types.WriteRequest{
+						PutRequest: &types.PutRequest{
+							Item: map[string]types.AttributeValue{
+								"Parent":  &types.AttributeValueMemberS{Value: PlatformParent},
+								"Child":   &types.AttributeValueMemberS{Value: Dep},
+								"Updated": &types.AttributeValueMemberS{Value: updated},
+							},
+						},
+					},
+				},
+			)
+		}
+		if dep.ManagedBy != "" {
+			item["ManagedBy"] = &types.AttributeValueMemberS{Value: dep.ManagedBy}
+		}
+		if dep.ManagedVersion != "" {
+			item["ManagedVersion"] = &types.AttributeValueMemberS{Value: dep.ManagedVersion}
+		}
+		if len(dep.Configurations) > 0 {
+			item["Configurations"] = &types.AttributeValueMemberSS{Value: helpers.Unique(dep.Configurations)}
+		}
+		if len(dep.Licenses) > 0 {
+			item["Licenses"] = &types.AttributeValueMemberSS{Value: helpers.Unique(dep.Licenses)}
+		}
+		if dep.Location != nil && dep.Location.File != "" {
+			item["File"] = &types.AttributeValueMemberS{Value: dep.Location.File}
+			if dep.Location.Line > 0 {
+				item["Line"] = &types.AttributeValueMemberN{Value: strconv.Itoa(dep.Location.Line)}
+			}
+		}
+		if len(parents[Dep]) > 0 {
+			item["Parents"] = &types.AttributeValueMemberSS{Value: helpers.Unique(parents[Dep])}
+		}
+		if len(deps.Edges) > 0 {
+			item["Direct"] = &types.AttributeValueMemberBOOL{Value: direct[Dep]}
+		}
+		if variant != "" {
+			item["Variant"] = &types.AttributeValueMemberS{Value: variant}
+			item["BuildType"] = &types.AttributeValueMemberS{Value: dep.Variant.BuildType}
+			if len(dep.Variant.Flavors) > 0 {
+				item["Flavors"] = &types.AttributeValueMemberSS{Value: helpers.Unique(dep.Variant.Flavors)}
+			}
+		}
+
 		insertBatch = append(insertBatch,
 			// Add info to dependencies table: groupId -> name
 			InsertItem{
@@ -92,16 +209,51 @@ func (svc *Storage) UpsertRepositoryInfo(ctxId string, repo string, ref string,
 					},
 				},
 			},
+			InsertItem{
+				Table: *svc.Config.StorageTableName,
+				Item: types.WriteRequest{
+					PutRequest: &types.PutRequest{
+						Item: item,
+					},
+				},
+			},
+		)
+	}
+
+	// Add plugins to storage. Plugin id is used as dependency, marker artifact is kept for reference
+	for _, plugin := range deps.Plugins {
+		Id := fmt.Sprintf("%s:%s:%s", repo, ref, plugin.Id)
+		marker := plugin.Marker
+		if marker == "" {
+			marker = fmt.Sprintf("%s:%s.gradle.plugin", plugin.Id, plugin.Id)
+		}
+
+		insertBatch = append(insertBatch,
+			// Add info to dependencies table: plugin root -> plugin id
+			InsertItem{
+				Table: *svc.Config.DependenciesTableName,
+				Item: types.WriteRequest{
+					PutRequest: &types.PutRequest{
+						Item: map[string]types.AttributeValue{
+							"Parent":  &types.AttributeValueMemberS{Value: PluginParent},
+							"Child":   &types.AttributeValueMemberS{Value: plugin.Id},
+							"Updated": &types.AttributeValueMemberS{Value: updated},
+						},
+					},
+				},
+			},
 			InsertItem{
 				Table: *svc.Config.StorageTableName,
 				Item: types.WriteRequest{
 					PutRequest: &types.PutRequest{
 						Item: map[string]types.AttributeValue{
 							"Id":         &types.AttributeValueMemberS{Value: Id},
-							"Dependency": &types.AttributeValueMemberS{Value: Dep},
-							"Version":    &types.AttributeValueMemberS{Value: dep.Version},
-							"Parent":     &types.AttributeValueMemberS{Value: repo},
-							"Child":      &types.AttributeValueMemberS{Value: ref},
+							"Dependency": &types.AttributeValueMemberS{Value: plugin.Id},
+							"Version":    &types.AttributeValueMemberS{Value: plugin.Version},
+							"Repo":       &types.AttributeValueMemberS{Value: repo},
+							"Ref":        &types.AttributeValueMemberS{Value: ref},
+							"Kind":       &types.AttributeValueMemberS{Value: KindPlugin},
+							"Marker":     &types.AttributeValueMemberS{Value: marker},
 							"Updated":    &types.AttributeValueMemberS{Value: updated},
 						},
 					},
@@ -109,6 +261,32 @@ func (svc *Storage) UpsertRepositoryInfo(ctxId string, repo string, ref string,
 			},
 		)
 	}
+	// Add build environment to storage. Every tool is stored as a separate pseudo dependency
+	if deps.Environment != nil {
+		for tool, version := range deps.Environment.Versions() {
+			Id := fmt.Sprintf("%s:%s:%s%s", repo, ref, EnvironmentPrefix, tool)
+
+			insertBatch = append(insertBatch,
+				InsertItem{
+					Table: *svc.Config.StorageTableName,
+					Item: types.WriteRequest{
+						PutRequest: &types.PutRequest{
+							Item: map[string]types.AttributeValue{
+								"Id":         &types.AttributeValueMemberS{Value: Id},
+								"Dependency": &types.AttributeValueMemberS{Value: EnvironmentPrefix + tool},
+								"Version":    &types.AttributeValueMemberS{Value: version},
+								"Repo":       &types.AttributeValueMemberS{Value: repo},
+								"Ref":        &types.AttributeValueMemberS{Value: ref},
+								"Kind":       &types.AttributeValueMemberS{Value: KindEnvironment},
+								"Updated":    &types.AttributeValueMemberS{Value: updated},
+							},
+						},
+					},
+				},
+			)
+		}
+	}
+
 	// Add info to dependencies table: root -> groupId
 	for group, _ := range groupsToInsert {
 		insertBatch = append(insertBatch,
@@ -155,6 +333,31 @@ func (svc *Storage) UpsertRepositoryInfo(ctxId string, repo string, ref string,
 		},
 	)
 
+	result, err := svc.batchWrite(ctxId, "UpsertRepositoryInfo", insertBatch,
+		map[string]string{
+			"repo": repo,
+			"ref":  ref,
+		},
+		zap.String("repo", repo),
+		zap.String("ref", ref),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s UpsertRepositoryInfo()", ctxId),
+		zap.String("repo", repo),
+		zap.String("ref", ref),
+	)
+
+	return result, nil
+}
+
+// batchWrite sends items with BatchWriteItem by 25, retrying unprocessed ones.
+// Items with the same key are collapsed (the last one wins), DynamoDB rejects duplicates inside one batch
+func (svc *Storage) batchWrite(ctxId string, method string, insertBatch []InsertItem, keys map[string]string, fields ...zap.Field) (*UpsertResultRest, *StorageErrorRest) {
+	insertBatch = svc.uniqueItems(insertBatch)
+
 	retry := 5
 	result := UpsertResultRest{
 		0,
@@ -176,30 +379,24 @@ func (svc *Storage) UpsertRepositoryInfo(ctxId string, repo string, ref string,
 			}
 		}
 
-		svc.Logger.Debug(fmt.Sprintf("%s UpsertRepositoryInfo()", ctxId),
-			zap.String("repo", repo),
-			zap.String("ref", ref),
-			zap.Reflect("params", params),
+		svc.Logger.Debug(fmt.Sprintf("%s %s()", ctxId, method),
+			append(fields, zap.Reflect("params", params))...,
 		)
 
 		resp, err := svc.DynamoDb.BatchWriteItem(context.Background(), params)
 		if err != nil {
-			return nil, svc.handleError(ctxId, err, "UpsertRepositoryInfo",
-				map[string]string{
-					"repo": repo,
-					"ref":  ref,
-				},
-				zap.String("repo", repo),
-				zap.String("ref", ref),
-				zap.Reflect("params", params),
+			return nil, svc.handleError(ctxId, err, method, keys,
+				append(fields, zap.Reflect("params", params))...,
 			)
 		}
-		svc.Logger.Debug(fmt.Sprintf("%s UpsertRepositoryInfo()", ctxId),
-			zap.String("repo", repo),
-			zap.String("ref", ref),
-			zap.Reflect("resp", resp),
+		svc.Logger.Debug(fmt.Sprintf("%s %s()", ctxId, method),
+			append(fields, zap.Reflect("resp", resp))...,
 		)
-		result.UsedCapacity = result.UsedCapacity + *resp.ConsumedCapacity[0].CapacityUnits
+		for _, capacity := range resp.ConsumedCapacity {
+			if capacity.CapacityUnits != nil {
+				result.UsedCapacity = result.UsedCapacity + *capacity.CapacityUnits
+			}
+		}
 
 		if len(insertBatch) > 25 {
 			insertBatch = insertBatch[25:]
@@ -217,17 +414,96 @@ func (svc *Storage) UpsertRepositoryInfo(ctxId string, repo string, ref string,
 					})
 				}
 			}
+			time.Sleep(time.Duration(5-retry) * 100 * time.Millisecond)
 		}
 	}
 
-	svc.Logger.Debug(fmt.Sprintf("%s UpsertRepositoryInfo()", ctxId),
-		zap.String("repo", repo),
-		zap.String("ref", ref),
-	)
+	if len(insertBatch) > 0 {
+		return nil, svc.handleError(ctxId, fmt.Errorf("%d items left unprocessed", len(insertBatch)), method, keys, fields...)
+	}
 
 	return &result, nil
 }
 
+// batchGet reads items by 100 keys with BatchGetItem, unprocessed keys are retried with the next call after a pause
+func (svc *Storage) batchGet(ctxId string, method string, table string, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, *StorageErrorRest) {
+	var result []map[string]types.AttributeValue
+	retry := 5
+	for len(keys) > 0 && retry > 0 {
+		size := helpers.Min(100, len(keys))
+		resp, err := svc.DynamoDb.BatchGetItem(context.TODO(), &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{
+				table: {Keys: keys[:size]},
+			},
+		})
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, method, map[string]string{}, zap.String("table", table))
+		}
+		keys = keys[size:]
+		result = append(result, resp.Responses[table]...)
+
+		if unprocessed, ok := resp.UnprocessedKeys[table]; ok && len(unprocessed.Keys) > 0 {
+			keys = append(keys, unprocessed.Keys...)
+			retry--
+			time.Sleep(time.Duration(5-retry) * 100 * time.Millisecond)
+		}
+	}
+
+	if len(keys) > 0 {
+		return nil, svc.handleError(ctxId, fmt.Errorf("%d keys are not read after retries", len(keys)), method,
+			map[string]string{}, zap.String("table", table))
+	}
+	return result, nil
+}
+
+func (svc *Storage) uniqueItems(items []InsertItem) []InsertItem {
+	index := make(map[string]int, len(items))
+	result := make([]InsertItem, 0, len(items))
+	for _, item := range items {
+		key := item.Table
+		var attrs map[string]types.AttributeValue
+		if item.Item.PutRequest != nil {
+			attrs = item.Item.PutRequest.Item
+		} else if item.Item.DeleteRequest != nil {
+			attrs = item.Item.DeleteRequest.Key
+		}
+		for _, name := range svc.tableKeys(item.Table) {
+			if val, ok := attrs[name].(*types.AttributeValueMemberS); ok {
+				key = key + "\x00" + val.Value
+			}
+		}
+
+		if idx, ok := index[key]; ok {
+			result[idx] = item
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, item)
+	}
+	return result
+}
+
+func (svc *Storage) tableKeys(table string) []string {
+	switch table {
+	case ptr.ToString(svc.Config.StorageTableName), ptr.ToString(svc.Config.TokensTableName),
+		ptr.ToString(svc.Config.AuditTableName), ptr.ToString(svc.Config.PoliciesTableName),
+		ptr.ToString(svc.Config.WebhooksTableName), ptr.ToString(svc.Config.DeliveriesTableName):
+		return []string{"Id"}
+	case ptr.ToString(svc.Config.AdvisoriesTableName):
+		return []string{"Package", "Id"}
+	case ptr.ToString(svc.Config.ViolationsTableName):
+		return []string{"Id", "Key"}
+	case ptr.ToString(svc.Config.LicensesTableName), ptr.ToString(svc.Config.MetadataTableName):
+		return []string{"Dependency", "Version"}
+	case ptr.ToString(svc.Config.FreshnessTableName):
+		return []string{"Id", "Date"}
+	case ptr.ToString(svc.Config.ProducersTableName):
+		return []string{"Artifact"}
+	default:
+		return []string{"Parent", "Child"}
+	}
+}
+
 func (svc *Storage) handleError(ctxId string, err error, method string, keys map[string]string, fields ...zap.Field) *StorageErrorRest {
 	var oe *smithy.OperationError
 	var errApi *smithy.GenericAPIError
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+func (svc *Storage) PutToken(ctxId string, token TokenDto) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s PutToken() called", ctxId),
+		zap.String("id", token.Id),
+		zap.String("owner", token.Owner),
+	)
+
+	item, err := attributevalue.MarshalMap(token)
+	if err != nil {
+		return svc.handleError(ctxId, err, "PutToken",
+			map[string]string{
+				"id": token.Id,
+			},
+			zap.String("id", token.Id),
+		)
+	}
+
+	_, err = svc.DynamoDb.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:           svc.Config.TokensTableName,
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(Id)"),
+	})
+	if err != nil {
+		return svc.handleError(ctxId, err, "PutToken",
+			map[string]string{
+				"id": token.Id,
+			},
+			zap.String("id", token.Id),
+		)
+	}
+
+	return nil
+}
+
+func (svc *Storage) GetToken(ctxId string, id string) (*TokenDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s GetToken() called", ctxId),
+		zap.String("id", id),
+	)
+
+	var consistentRead = true
+	resp, err := svc.DynamoDb.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName:      svc.Config.TokensTableName,
+		ConsistentRead: &consistentRead,
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, svc.handleError(ctxId, err, "GetToken",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+	if resp.Item == nil {
+		return nil, &StorageErrorRest{
+			Message: fmt.Sprintf("Error #%d Data Not Found", ErrObjectNotFound),
+			Code:    ErrObjectNotFound,
+			Id:      id,
+		}
+	}
+
+	var token TokenDto
+	if err := attributevalue.UnmarshalMap(resp.Item, &token); err != nil {
+		return nil, svc.handleError(ctxId, err, "GetToken",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+
+	return &token, nil
+}
+
+func (svc *Storage) ListTokensByOwner(ctxId string, owner string) (*[]TokenDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s ListTokensByOwner() called", ctxId),
+		zap.String("owner", owner),
+	)
+
+	var consistentRead = false
+	params := &dynamodb.QueryInput{
+		TableName:              svc.Config.TokensTableName,
+		IndexName:              ptr.String("Owner"),
+		ConsistentRead:         &consistentRead,
+		KeyConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner": &types.AttributeValueMemberS{Value: owner},
+		},
+		ExpressionAttributeNames: map[string]string{
+			"#owner": "Owner",
+		},
+	}
+	paginator := dynamodb.NewQueryPaginator(svc.DynamoDb, params)
+
+	result := []TokenDto{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ListTokensByOwner",
+				map[string]string{},
+				zap.String("owner", owner),
+			)
+		}
+
+		var tokensResp []TokenDto
+		err = attributevalue.UnmarshalListOfMaps(page.Items, &tokensResp)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "ListTokensByOwner",
+				map[string]string{},
+				zap.String("owner", owner),
+			)
+		}
+		result = append(result, tokensResp...)
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListTokensByOwner() result", ctxId),
+		zap.String("owner", owner),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
+
+// RevokeToken marks token as revoked, only owner of the token is able to revoke it
+func (svc *Storage) RevokeToken(ctxId string, id string, owner string) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s RevokeToken() called", ctxId),
+		zap.String("id", id),
+		zap.String("owner", owner),
+	)
+
+	_, err := svc.DynamoDb.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
+		TableName: svc.Config.TokensTableName,
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:    aws.String("SET Revoked = :revoked"),
+		ConditionExpression: aws.String("attribute_exists(Id) and #owner = :owner"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner": "Owner",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner":   &types.AttributeValueMemberS{Value: owner},
+			":revoked": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return &StorageErrorRest{
+				Message: fmt.Sprintf("Error #%d Data Not Found", ErrObjectNotFound),
+				Code:    ErrObjectNotFound,
+				Id:      id,
+				Err:     err,
+			}
+		}
+		return svc.handleError(ctxId, err, "RevokeToken",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+
+	return nil
+}
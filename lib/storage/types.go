@@ -3,12 +3,60 @@ package storage
 type (
 	DependenciesRest struct {
 		Dependencies []DependencyRest `json:"dependencies"`
+		Plugins      []PluginRest     `json:"plugins"`
+		Environment  *EnvironmentRest `json:"environment"`
+		Edges        []EdgeRest       `json:"edges"`
+		// Produces lists group:name artifacts published by uploaded repository
+		Produces []string `json:"produces,omitempty"`
 	}
 
 	DependencyRest struct {
-		Group   string `json:"group"`
-		Name    string `json:"name"`
+		Group          string        `json:"group"`
+		Name           string        `json:"name"`
+		Version        string        `json:"version"`
+		Platform       string        `json:"platform"`
+		ManagedBy      string        `json:"managed-by"`
+		ManagedVersion string        `json:"managed-version"`
+		Variant        *VariantRest  `json:"variant"`
+		Configurations []string      `json:"configurations"`
+		Licenses       []string      `json:"licenses,omitempty"`
+		Location       *LocationRest `json:"location,omitempty"`
+	}
+
+	// LocationRest is build file where dependency is declared, e.g. gradle.lockfile line, path is relative to repository root
+	LocationRest struct {
+		File string `json:"file"`
+		Line int    `json:"line,omitempty"`
+	}
+
+	// EdgeRest links requesting dependency (group:name, empty for project itself) with requested one
+	EdgeRest struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	VariantRest struct {
+		BuildType string   `json:"build-type"`
+		Flavors   []string `json:"flavors"`
+	}
+
+	PluginRest struct {
+		Id      string `json:"id"`
 		Version string `json:"version"`
+		Marker  string `json:"marker"`
+	}
+
+	EnvironmentRest struct {
+		GradleDistributionUrl string `json:"gradle-distribution-url"`
+		Gradle                string `json:"gradle"`
+		JavaToolchain         string `json:"java-toolchain"`
+		Kotlin                string `json:"kotlin"`
+	}
+
+	StorageFilter struct {
+		Variant   string
+		BuildType string
+		Flavor    string
 	}
 
 	UpsertResultRest struct {
@@ -28,19 +76,205 @@ type (
 
 type (
 	DependencyDto struct {
-		Parent string `dynamodbav:"Parent"`
-		Child  string `dynamodbav:"Child"`
+		Parent  string `dynamodbav:"Parent" json:"parent"`
+		Child   string `dynamodbav:"Child" json:"child"`
+		Updated string `dynamodbav:"Updated,omitempty" json:"updated,omitempty"`
+	}
+
+	EnvironmentInventoryDto struct {
+		Tool     string                  `json:"tool"`
+		Versions map[string][]StorageDto `json:"versions"`
 	}
 
 	RepositoryDto struct {
-		Parent string `dynamodbav:"Parent"`
-		Child  string `dynamodbav:"Child"`
+		Parent  string `dynamodbav:"Parent" json:"parent"`
+		Child   string `dynamodbav:"Child" json:"child"`
+		Updated string `dynamodbav:"Updated,omitempty" json:"updated,omitempty"`
 	}
 
 	StorageDto struct {
-		Dependency string `dynamodbav:"Dependency"`
-		Version    string `dynamodbav:"Version"`
-		Repo       string `dynamodbav:"Repo"`
-		Ref        string `dynamodbav:"Ref"`
+		Id             string   `dynamodbav:"Id,omitempty" json:"id,omitempty"`
+		Dependency     string   `dynamodbav:"Dependency" json:"dependency"`
+		Version        string   `dynamodbav:"Version" json:"version"`
+		Repo           string   `dynamodbav:"Repo" json:"repo"`
+		Ref            string   `dynamodbav:"Ref" json:"ref"`
+		Kind           string   `dynamodbav:"Kind" json:"kind"`
+		Marker         string   `dynamodbav:"Marker,omitempty" json:"marker,omitempty"`
+		Platform       string   `dynamodbav:"Platform,omitempty" json:"platform,omitempty"`
+		ManagedBy      string   `dynamodbav:"ManagedBy,omitempty" json:"managed-by,omitempty"`
+		ManagedVersion string   `dynamodbav:"ManagedVersion,omitempty" json:"managed-version,omitempty"`
+		Variant        string   `dynamodbav:"Variant,omitempty" json:"variant,omitempty"`
+		BuildType      string   `dynamodbav:"BuildType,omitempty" json:"build-type,omitempty"`
+		Flavors        []string `dynamodbav:"Flavors,stringset,omitempty" json:"flavors,omitempty"`
+		Configurations []string `dynamodbav:"Configurations,stringset,omitempty" json:"configurations,omitempty"`
+		Parents        []string `dynamodbav:"Parents,stringset,omitempty" json:"parents,omitempty"`
+		Direct         bool     `dynamodbav:"Direct,omitempty" json:"direct,omitempty"`
+		Licenses       []string `dynamodbav:"Licenses,stringset,omitempty" json:"licenses,omitempty"`
+		File           string   `dynamodbav:"File,omitempty" json:"file,omitempty"`
+		Line           int      `dynamodbav:"Line,omitempty" json:"line,omitempty"`
+		Updated        string   `dynamodbav:"Updated,omitempty" json:"updated,omitempty"`
+
+		// Outdated is not stored, views annotate items from Maven metadata
+		Outdated *OutdatedDto `dynamodbav:"-" json:"outdated,omitempty"`
+	}
+
+	// OutdatedDto compares used version with the latest release, Released and LatestReleased are RFC3339 times when known
+	OutdatedDto struct {
+		Latest         string `json:"latest"`
+		Behind         int    `json:"behind"`
+		AgeDays        int    `json:"age-days"`
+		Released       string `json:"released,omitempty"`
+		LatestReleased string `json:"latest-released,omitempty"`
+	}
+
+	// MetadataDto caches Maven repository: versions of artifact for Version MetadataAnyVersion,
+	// publication time for other versions. Missing marks artifacts absent in repository
+	MetadataDto struct {
+		Dependency string   `dynamodbav:"Dependency" json:"dependency"`
+		Version    string   `dynamodbav:"Version" json:"version"`
+		Versions   []string `dynamodbav:"Versions,omitempty" json:"versions,omitempty"`
+		Release    string   `dynamodbav:"Release,omitempty" json:"release,omitempty"`
+		Published  string   `dynamodbav:"Published,omitempty" json:"published,omitempty"`
+		Missing    bool     `dynamodbav:"Missing,omitempty" json:"missing,omitempty"`
+		Checked    string   `dynamodbav:"Checked" json:"checked"`
+		Expires    int64    `dynamodbav:"Expires" json:"expires"`
+	}
+
+	TokenDto struct {
+		Id      string   `dynamodbav:"Id" json:"id"`
+		Hash    string   `dynamodbav:"Hash" json:"-"`
+		Name    string   `dynamodbav:"Name" json:"name"`
+		Owner   string   `dynamodbav:"Owner" json:"owner"`
+		Role    string   `dynamodbav:"Role,omitempty" json:"role,omitempty"`
+		Scopes  []string `dynamodbav:"Scopes,stringset,omitempty" json:"scopes,omitempty"`
+		Created string   `dynamodbav:"Created" json:"created"`
+		Expires string   `dynamodbav:"Expires" json:"expires"`
+		Revoked string   `dynamodbav:"Revoked,omitempty" json:"revoked,omitempty"`
+	}
+
+	AuditDto struct {
+		Id          string `dynamodbav:"Id" json:"id"`
+		Time        string `dynamodbav:"Time" json:"time"`
+		Principal   string `dynamodbav:"Principal" json:"principal"`
+		Action      string `dynamodbav:"Action" json:"action"`
+		Repo        string `dynamodbav:"Repo,omitempty" json:"repo,omitempty"`
+		Ref         string `dynamodbav:"Ref,omitempty" json:"ref,omitempty"`
+		Target      string `dynamodbav:"Target,omitempty" json:"target,omitempty"`
+		Count       int    `dynamodbav:"Count" json:"count"`
+		SourceIp    string `dynamodbav:"SourceIp" json:"source-ip"`
+		RequestId   string `dynamodbav:"RequestId" json:"request-id"`
+		PayloadHash string `dynamodbav:"PayloadHash,omitempty" json:"payload-hash,omitempty"`
+	}
+
+	// AuditFilter selects audit entries by principal or repo within optional time range formatted with AuditTimeLayout
+	AuditFilter struct {
+		Principal string
+		Repo      string
+		From      string
+		To        string
+	}
+
+	// AdvisoryDto is advisory affecting single package, Package is group:name or AliasPrefix+alias for lookup by CVE
+	AdvisoryDto struct {
+		Package  string   `dynamodbav:"Package" json:"package"`
+		Id       string   `dynamodbav:"Id" json:"id"`
+		Aliases  []string `dynamodbav:"Aliases,stringset,omitempty" json:"aliases,omitempty"`
+		Summary  string   `dynamodbav:"Summary,omitempty" json:"summary,omitempty"`
+		Severity string   `dynamodbav:"Severity,omitempty" json:"severity,omitempty"`
+		Ranges   []string `dynamodbav:"Ranges,stringset,omitempty" json:"ranges,omitempty"`
+		Packages []string `dynamodbav:"Packages,stringset,omitempty" json:"packages,omitempty"`
+		Modified string   `dynamodbav:"Modified,omitempty" json:"modified,omitempty"`
+	}
+
+	// LicenseDto maps artifact to licenses, Version is LicenseAnyVersion when mapping applies to every version
+	LicenseDto struct {
+		Dependency string   `dynamodbav:"Dependency" json:"dependency"`
+		Version    string   `dynamodbav:"Version" json:"version"`
+		Licenses   []string `dynamodbav:"Licenses,stringset" json:"licenses"`
+		Source     string   `dynamodbav:"Source,omitempty" json:"source,omitempty"`
+	}
+
+	// FreshnessDto is daily snapshot of repo/ref dependency freshness, Id is repo:ref and Date is YYYY-MM-DD.
+	// Resolved counts dependencies found in Maven repository, Libyears sums gaps between used and latest releases
+	FreshnessDto struct {
+		Id           string  `dynamodbav:"Id" json:"id"`
+		Date         string  `dynamodbav:"Date" json:"date"`
+		Repo         string  `dynamodbav:"Repo" json:"repo"`
+		Ref          string  `dynamodbav:"Ref" json:"ref"`
+		Dependencies int     `dynamodbav:"Dependencies" json:"dependencies"`
+		Resolved     int     `dynamodbav:"Resolved" json:"resolved"`
+		Current      int     `dynamodbav:"Current" json:"current"`
+		CurrentShare float64 `dynamodbav:"CurrentShare" json:"current-share"`
+		Libyears     float64 `dynamodbav:"Libyears" json:"libyears"`
+		Updated      string  `dynamodbav:"Updated,omitempty" json:"updated,omitempty"`
+	}
+
+	// ProducerDto links artifact group:name with repository publishing it, as declared by the latest upload
+	ProducerDto struct {
+		Artifact string `dynamodbav:"Artifact" json:"artifact"`
+		Repo     string `dynamodbav:"Repo" json:"repo"`
+		Ref      string `dynamodbav:"Ref,omitempty" json:"ref,omitempty"`
+		Updated  string `dynamodbav:"Updated,omitempty" json:"updated,omitempty"`
+	}
+
+	// PolicyDto keeps policy document as uploaded, YAML or JSON
+	PolicyDto struct {
+		Id        string `dynamodbav:"Id" json:"id"`
+		Document  string `dynamodbav:"Document" json:"document"`
+		Updated   string `dynamodbav:"Updated" json:"updated"`
+		UpdatedBy string `dynamodbav:"UpdatedBy" json:"updated-by"`
+	}
+
+	// ViolationDto is policy rule violated by dependency of repo/ref, Id is repo:ref
+	ViolationDto struct {
+		Id             string   `dynamodbav:"Id" json:"-"`
+		Key            string   `dynamodbav:"Key" json:"-"`
+		Repo           string   `dynamodbav:"Repo" json:"repo"`
+		Ref            string   `dynamodbav:"Ref" json:"ref"`
+		Rule           string   `dynamodbav:"Rule" json:"rule"`
+		Description    string   `dynamodbav:"Description,omitempty" json:"description,omitempty"`
+		Dependency     string   `dynamodbav:"Dependency" json:"dependency"`
+		Version        string   `dynamodbav:"Version" json:"version"`
+		Configurations []string `dynamodbav:"Configurations,stringset,omitempty" json:"configurations,omitempty"`
+		Licenses       []string `dynamodbav:"Licenses,stringset,omitempty" json:"licenses,omitempty"`
+		Updated        string   `dynamodbav:"Updated,omitempty" json:"updated,omitempty"`
+	}
+
+	// WebhookDto is subscription to dependency changes, Repos (org/repo), Refs and Artifacts (group:name) are globs,
+	// empty ones match anything. Secret signs deliveries, it is returned only when webhook is created
+	WebhookDto struct {
+		Id          string   `dynamodbav:"Id" json:"id"`
+		Url         string   `dynamodbav:"Url" json:"url"`
+		Secret      string   `dynamodbav:"Secret" json:"-"`
+		Description string   `dynamodbav:"Description,omitempty" json:"description,omitempty"`
+		Repos       []string `dynamodbav:"Repos,stringset,omitempty" json:"repos,omitempty"`
+		Refs        []string `dynamodbav:"Refs,stringset,omitempty" json:"refs,omitempty"`
+		Artifacts   []string `dynamodbav:"Artifacts,stringset,omitempty" json:"artifacts,omitempty"`
+		Created     string   `dynamodbav:"Created" json:"created"`
+		CreatedBy   string   `dynamodbav:"CreatedBy" json:"created-by"`
+	}
+
+	// DeliveryDto is event sent or to be sent to webhook, Id is event id:webhook id and Event is JSON body as signed
+	DeliveryDto struct {
+		Id          string `dynamodbav:"Id" json:"id"`
+		Webhook     string `dynamodbav:"Webhook" json:"webhook"`
+		Repo        string `dynamodbav:"Repo" json:"repo"`
+		Ref         string `dynamodbav:"Ref" json:"ref"`
+		Event       string `dynamodbav:"Event" json:"event"`
+		Status      string `dynamodbav:"Status" json:"status"`
+		Attempts    int    `dynamodbav:"Attempts" json:"attempts"`
+		NextAttempt string `dynamodbav:"NextAttempt,omitempty" json:"next-attempt,omitempty"`
+		LastError   string `dynamodbav:"LastError,omitempty" json:"last-error,omitempty"`
+		Updated     string `dynamodbav:"Updated" json:"updated"`
+		Expires     int64  `dynamodbav:"Expires" json:"expires"`
+	}
+
+	PlatformUsageDto struct {
+		Repo      string       `json:"repo"`
+		Ref       string       `json:"ref"`
+		Version   string       `json:"version"`
+		Platform  string       `json:"platform"`
+		Managed   []StorageDto `json:"managed"`
+		Overrides []StorageDto `json:"overrides"`
 	}
 )
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gradle-serverless-dependencies-graph/lib/helpers"
+	"strings"
+)
+
+func (v VariantRest) Name() string {
+	return helpers.AndroidVariantName(v.BuildType, v.Flavors)
+}
+
+// NewStorageFilter builds filter from query string parameters, returns nil when nothing to filter
+func NewStorageFilter(params map[string]string) *StorageFilter {
+	filter := StorageFilter{
+		Variant:   params["variant"],
+		BuildType: params["build-type"],
+		Flavor:    params["flavor"],
+	}
+	if filter == (StorageFilter{}) {
+		return nil
+	}
+	return &filter
+}
+
+// apply adds FilterExpression to query. Items without variant (plugins, environment, non-android projects)
+// are shared by all variants and always pass the filter
+func (filter *StorageFilter) apply(params *dynamodb.QueryInput) {
+	if filter == nil {
+		return
+	}
+
+	if params.ExpressionAttributeNames == nil {
+		params.ExpressionAttributeNames = make(map[string]string)
+	}
+	var conditions []string
+	if filter.Variant != "" {
+		conditions = append(conditions, "#variant = :variant")
+		params.ExpressionAttributeNames["#variant"] = "Variant"
+		params.ExpressionAttributeValues[":variant"] = &types.AttributeValueMemberS{Value: filter.Variant}
+	}
+	if filter.BuildType != "" {
+		conditions = append(conditions, "#buildType = :buildType")
+		params.ExpressionAttributeNames["#buildType"] = "BuildType"
+		params.ExpressionAttributeValues[":buildType"] = &types.AttributeValueMemberS{Value: filter.BuildType}
+	}
+	if filter.Flavor != "" {
+		conditions = append(conditions, "contains(#flavors, :flavor)")
+		params.ExpressionAttributeNames["#flavors"] = "Flavors"
+		params.ExpressionAttributeValues[":flavor"] = &types.AttributeValueMemberS{Value: filter.Flavor}
+	}
+	if len(conditions) == 0 {
+		return
+	}
+	params.ExpressionAttributeNames["#variantAny"] = "Variant"
+
+	params.FilterExpression = aws.String("attribute_not_exists(#variantAny) or (" + strings.Join(conditions, " and ") + ")")
+}
+
+// Match applies filter to already loaded item with the same rules as DynamoDB FilterExpression
+func (filter *StorageFilter) Match(item StorageDto) bool {
+	if filter == nil || item.Variant == "" {
+		return true
+	}
+	if filter.Variant != "" && filter.Variant != item.Variant {
+		return false
+	}
+	if filter.BuildType != "" && filter.BuildType != item.BuildType {
+		return false
+	}
+	if filter.Flavor != "" {
+		for _, flavor := range item.Flavors {
+			if flavor == filter.Flavor {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+func (svc *Storage) PutWebhook(ctxId string, webhook WebhookDto) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s PutWebhook() called", ctxId),
+		zap.String("id", webhook.Id),
+		zap.String("url", webhook.Url),
+	)
+
+	item, err := attributevalue.MarshalMap(webhook)
+	if err == nil {
+		_, err = svc.DynamoDb.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName:           svc.Config.WebhooksTableName,
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(Id)"),
+		})
+	}
+	if err != nil {
+		return svc.handleError(ctxId, err, "PutWebhook",
+			map[string]string{
+				"id": webhook.Id,
+			},
+			zap.String("id", webhook.Id),
+		)
+	}
+	return nil
+}
+
+func (svc *Storage) ListWebhooks(ctxId string) (*[]WebhookDto, *StorageErrorRest) {
+	result := []WebhookDto{}
+	errScan := svc.scan(ctxId, "ListWebhooks", svc.Config.WebhooksTableName, func(item map[string]types.AttributeValue) error {
+		var webhook WebhookDto
+		if err := attributevalue.UnmarshalMap(item, &webhook); err != nil {
+			return err
+		}
+		result = append(result, webhook)
+		return nil
+	})
+	if errScan != nil {
+		return nil, errScan
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListWebhooks() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
+
+// DeleteWebhook removes subscription, deliveries already queued for it fail on next attempt
+func (svc *Storage) DeleteWebhook(ctxId string, id string) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s DeleteWebhook() called", ctxId),
+		zap.String("id", id),
+	)
+
+	_, err := svc.DynamoDb.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: svc.Config.WebhooksTableName,
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression: aws.String("attribute_exists(Id)"),
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return &StorageErrorRest{
+				Message: fmt.Sprintf("Error #%d Data Not Found", ErrObjectNotFound),
+				Code:    ErrObjectNotFound,
+				Id:      id,
+				Err:     err,
+			}
+		}
+		return svc.handleError(ctxId, err, "DeleteWebhook",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+	return nil
+}
+
+// PutDeliveries stores deliveries, new pending ones are picked up by dispatcher through table stream
+func (svc *Storage) PutDeliveries(ctxId string, deliveries []DeliveryDto) (*UpsertResultRest, *StorageErrorRest) {
+	keys := map[string]string{}
+	batch := make([]InsertItem, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		insert, err := svc.putItem(*svc.Config.DeliveriesTableName, delivery)
+		if err != nil {
+			return nil, svc.handleError(ctxId, err, "PutDeliveries", map[string]string{"id": delivery.Id})
+		}
+		batch = append(batch, insert)
+	}
+	return svc.batchWrite(ctxId, "PutDeliveries", batch, keys, zap.Int("count", len(deliveries)))
+}
+
+func (svc *Storage) GetDelivery(ctxId string, id string) (*DeliveryDto, *StorageErrorRest) {
+	svc.Logger.Debug(fmt.Sprintf("%s GetDelivery() called", ctxId),
+		zap.String("id", id),
+	)
+
+	var consistentRead = true
+	resp, err := svc.DynamoDb.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName:      svc.Config.DeliveriesTableName,
+		ConsistentRead: &consistentRead,
+		Key: map[string]types.AttributeValue{
+			"Id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, svc.handleError(ctxId, err, "GetDelivery",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+	if resp.Item == nil {
+		return nil, &StorageErrorRest{
+			Message: fmt.Sprintf("Error #%d Data Not Found", ErrObjectNotFound),
+			Code:    ErrObjectNotFound,
+			Id:      id,
+		}
+	}
+
+	var delivery DeliveryDto
+	if err := attributevalue.UnmarshalMap(resp.Item, &delivery); err != nil {
+		return nil, svc.handleError(ctxId, err, "GetDelivery",
+			map[string]string{
+				"id": id,
+			},
+			zap.String("id", id),
+		)
+	}
+
+	return &delivery, nil
+}
+
+func (svc *Storage) UpdateDelivery(ctxId string, delivery DeliveryDto) *StorageErrorRest {
+	svc.Logger.Debug(fmt.Sprintf("%s UpdateDelivery() called", ctxId),
+		zap.String("id", delivery.Id),
+		zap.String("status", delivery.Status),
+		zap.Int("attempts", delivery.Attempts),
+	)
+
+	item, err := attributevalue.MarshalMap(delivery)
+	if err == nil {
+		_, err = svc.DynamoDb.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName: svc.Config.DeliveriesTableName,
+			Item:      item,
+		})
+	}
+	if err != nil {
+		return svc.handleError(ctxId, err, "UpdateDelivery",
+			map[string]string{
+				"id": delivery.Id,
+			},
+			zap.String("id", delivery.Id),
+		)
+	}
+	return nil
+}
+
+// ListDueDeliveries returns pending deliveries with NextAttempt (RFC 3339, UTC) not after now,
+// table is small as delivered and failed items expire
+func (svc *Storage) ListDueDeliveries(ctxId string, now string) (*[]DeliveryDto, *StorageErrorRest) {
+	result := []DeliveryDto{}
+	errScan := svc.scan(ctxId, "ListDueDeliveries", svc.Config.DeliveriesTableName, func(item map[string]types.AttributeValue) error {
+		var delivery DeliveryDto
+		if err := attributevalue.UnmarshalMap(item, &delivery); err != nil {
+			return err
+		}
+		if delivery.Status == DeliveryPending && delivery.NextAttempt <= now {
+			result = append(result, delivery)
+		}
+		return nil
+	})
+	if errScan != nil {
+		return nil, errScan
+	}
+
+	svc.Logger.Debug(fmt.Sprintf("%s ListDueDeliveries() result", ctxId),
+		zap.Int("count", len(result)),
+	)
+
+	return &result, nil
+}
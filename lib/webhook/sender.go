@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultAttempts = 3
+	DefaultBackoff  = time.Second
+	DefaultTimeout  = 10 * time.Second
+)
+
+// Sender POSTs signed events, attempts are retried with exponential backoff while receiver is unavailable
+type Sender struct {
+	Client   *http.Client
+	Attempts int
+	Backoff  time.Duration
+}
+
+func NewSender() *Sender {
+	return &Sender{Client: &http.Client{Timeout: DefaultTimeout}, Attempts: DefaultAttempts, Backoff: DefaultBackoff}
+}
+
+// DeliveryError is failed delivery, Permanent ones are rejected by receiver and are not worth retrying
+type DeliveryError struct {
+	Attempts  int
+	Permanent bool
+	Err       error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("delivery failed after %d attempt(s): %s", e.Attempts, e.Err)
+}
+
+// Send POSTs body signed with secret to url, body is sent as is so signature matches it
+func (s *Sender) Send(ctx context.Context, url string, secret string, deliveryId string, body []byte) error {
+	signature := Sign(secret, body)
+	backoff := s.Backoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var permanent bool
+		if permanent, err = s.post(ctx, url, signature, deliveryId, body); err == nil {
+			return nil
+		}
+		if permanent || attempt >= s.Attempts {
+			return &DeliveryError{Attempts: attempt, Permanent: permanent, Err: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return &DeliveryError{Attempts: attempt, Err: err}
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (s *Sender) post(ctx context.Context, url string, signature string, deliveryId string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gdg-webhook")
+	req.Header.Set(HeaderEvent, EventType)
+	req.Header.Set(HeaderDelivery, deliveryId)
+	req.Header.Set(HeaderSignature, signature)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	// Receiver is overloaded or broken, it may recover
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		return false, fmt.Errorf("%s responded %s", url, resp.Status)
+	default:
+		return true, fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+}
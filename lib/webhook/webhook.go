@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"path"
+	"time"
+)
+
+const (
+	EventType = "dependencies.changed"
+
+	// MaxAttempts limits deliveries of event, retries are spread over about 2 hours by NextAttempt
+	MaxAttempts = 8
+	// Retention keeps delivered and failed deliveries for inspection before table TTL removes them
+	Retention = 7 * 24 * time.Hour
+
+	HeaderEvent     = "X-Gdg-Event"
+	HeaderDelivery  = "X-Gdg-Delivery"
+	HeaderSignature = "X-Gdg-Signature-256"
+)
+
+// Event describes dependencies changed by upload of repo/ref
+type Event struct {
+	Id      string         `json:"id"`
+	Type    string         `json:"type"`
+	Repo    string         `json:"repo"`
+	Ref     string         `json:"ref"`
+	Time    string         `json:"time"`
+	Added   []graph.Change `json:"added"`
+	Removed []graph.Change `json:"removed"`
+	Changed []graph.Change `json:"changed"`
+}
+
+// NewEvent splits changes of repo/ref, event is nil when nothing changed
+func NewEvent(repo string, ref string, changes []graph.Change, now time.Time) (*Event, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		Id:      hex.EncodeToString(id),
+		Type:    EventType,
+		Repo:    repo,
+		Ref:     ref,
+		Time:    now.UTC().Format(time.RFC3339),
+		Added:   []graph.Change{},
+		Removed: []graph.Change{},
+		Changed: []graph.Change{},
+	}
+	for _, change := range changes {
+		switch change.Change {
+		case graph.ChangeAdded:
+			event.Added = append(event.Added, change)
+		case graph.ChangeRemoved:
+			event.Removed = append(event.Removed, change)
+		default:
+			event.Changed = append(event.Changed, change)
+		}
+	}
+	return event, nil
+}
+
+// Items converts upload to items as they are stored, so it can be compared with previous upload by graph.Diff.
+// Build environment is not compared
+func Items(repo string, ref string, deps storage.DependenciesRest) []storage.StorageDto {
+	result := make([]storage.StorageDto, 0, len(deps.Dependencies)+len(deps.Plugins))
+	for _, dep := range deps.Dependencies {
+		item := storage.StorageDto{
+			Repo:       repo,
+			Ref:        ref,
+			Kind:       storage.KindLibrary,
+			Dependency: fmt.Sprintf("%s:%s", dep.Group, dep.Name),
+			Version:    dep.Version,
+		}
+		if dep.Platform != "" {
+			item.Kind = storage.KindPlatform
+		}
+		if dep.Variant != nil {
+			item.Variant = dep.Variant.Name()
+		}
+		result = append(result, item)
+	}
+	for _, plugin := range deps.Plugins {
+		result = append(result, storage.StorageDto{Repo: repo, Ref: ref, Kind: storage.KindPlugin, Dependency: plugin.Id, Version: plugin.Version})
+	}
+	return result
+}
+
+// LastUpload returns stored items written by the most recent upload of repo/ref. Upload does not delete
+// items missing in it, so older items are leftovers of previous uploads and were already reported as removed
+func LastUpload(items []storage.StorageDto) []storage.StorageDto {
+	var latest string
+	for _, item := range items {
+		if item.Kind != storage.KindEnvironment && item.Updated > latest {
+			latest = item.Updated
+		}
+	}
+	var result []storage.StorageDto
+	for _, item := range items {
+		if item.Kind != storage.KindEnvironment && item.Updated == latest {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// For returns event as seen by webhook: nil when repo/ref is not subscribed, only subscribed artifacts otherwise
+func For(webhook storage.WebhookDto, event Event) *Event {
+	if !matchesAny(webhook.Repos, event.Repo) || !matchesAny(webhook.Refs, event.Ref) {
+		return nil
+	}
+	if len(webhook.Artifacts) == 0 {
+		return &event
+	}
+
+	filter := func(changes []graph.Change) []graph.Change {
+		result := []graph.Change{}
+		for _, change := range changes {
+			if matchesAny(webhook.Artifacts, change.Dependency) {
+				result = append(result, change)
+			}
+		}
+		return result
+	}
+	event.Added, event.Removed, event.Changed = filter(event.Added), filter(event.Removed), filter(event.Changed)
+	if len(event.Added)+len(event.Removed)+len(event.Changed) == 0 {
+		return nil
+	}
+	return &event
+}
+
+// Validate checks webhook patterns, so malformed ones are rejected when webhook is created
+func Validate(webhook storage.WebhookDto) error {
+	patterns := append(append(append([]string{}, webhook.Repos...), webhook.Refs...), webhook.Artifacts...)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("malformed pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Sign returns value of HeaderSignature, receiver computes HMAC-SHA256 of raw body with shared secret and compares
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns random secret shared with webhook receiver
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// NextAttempt returns time of retry after failed attempt: 1, 2, 4 ... minutes, at most an hour
+func NextAttempt(attempts int, now time.Time) time.Time {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return now.Add(delay)
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
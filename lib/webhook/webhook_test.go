@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"gradle-serverless-dependencies-graph/lib/graph"
+	"gradle-serverless-dependencies-graph/lib/storage"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvent(t *testing.T) {
+	previous := []storage.StorageDto{
+		{Kind: storage.KindLibrary, Dependency: "com.acme:removed", Version: "0.9", Updated: "2026-01-01T00:00:00Z"},
+		{Kind: storage.KindLibrary, Dependency: "com.acme:core", Version: "1.0", Updated: "2026-02-01T00:00:00Z"},
+		{Kind: storage.KindLibrary, Dependency: "com.acme:client", Version: "1.0", Updated: "2026-02-01T00:00:00Z"},
+		{Kind: storage.KindEnvironment, Dependency: "gradle", Version: "8.5", Updated: "2026-03-01T00:00:00Z"},
+	}
+	deps := storage.DependenciesRest{
+		Dependencies: []storage.DependencyRest{
+			{Group: "com.acme", Name: "core", Version: "1.1"},
+			{Group: "com.acme", Name: "bom", Version: "2.0", Platform: "platform"},
+		},
+		Plugins: []storage.PluginRest{{Id: "org.jetbrains.kotlin.jvm", Version: "1.9.22"}},
+	}
+
+	changes := graph.Diff(LastUpload(previous), Items("acme/api", "main", deps))
+	event, err := NewEvent("acme/api", "main", changes, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Id == "" || event.Type != EventType || event.Time != "2026-03-02T00:00:00Z" {
+		t.Errorf("Wrong event %+v", event)
+	}
+	if len(event.Added) != 2 || len(event.Changed) != 1 || len(event.Removed) != 1 {
+		t.Fatalf("Wrong changes %+v", event)
+	}
+	if event.Removed[0].Dependency != "com.acme:client" {
+		t.Errorf("Leftover of older upload should not be removed again, got %+v", event.Removed)
+	}
+	if event.Changed[0].Before != "1.0" || event.Changed[0].After != "1.1" {
+		t.Errorf("Wrong change %+v", event.Changed[0])
+	}
+
+	if event, _ := NewEvent("acme/api", "main", nil, time.Now()); event != nil {
+		t.Errorf("Expected no event without changes, got %+v", event)
+	}
+}
+
+func TestFor(t *testing.T) {
+	event := Event{
+		Repo:    "acme/api",
+		Ref:     "release/1.0",
+		Added:   []graph.Change{{Change: graph.ChangeAdded, Dependency: "com.acme:core"}},
+		Changed: []graph.Change{{Change: graph.ChangeChanged, Dependency: "org.slf4j:slf4j-api"}},
+		Removed: []graph.Change{},
+	}
+	cases := []struct {
+		webhook  storage.WebhookDto
+		expected int
+	}{
+		{storage.WebhookDto{}, 2},
+		{storage.WebhookDto{Repos: []string{"acme/*"}, Refs: []string{"main", "release/*"}}, 2},
+		{storage.WebhookDto{Repos: []string{"other/*"}}, -1},
+		{storage.WebhookDto{Refs: []string{"main"}}, -1},
+		{storage.WebhookDto{Artifacts: []string{"com.acme:*"}}, 1},
+		{storage.WebhookDto{Artifacts: []string{"log4j:log4j"}}, -1},
+	}
+	for _, c := range cases {
+		result := For(c.webhook, event)
+		switch {
+		case c.expected < 0 && result != nil:
+			t.Errorf("%+v: expected no event, got %+v", c.webhook, result)
+		case c.expected >= 0 && (result == nil || len(result.Added)+len(result.Changed)+len(result.Removed) != c.expected):
+			t.Errorf("%+v: expected %d changes, got %+v", c.webhook, c.expected, result)
+		}
+	}
+	if len(event.Changed) != 1 {
+		t.Error("Event of other webhooks is changed")
+	}
+
+	if err := Validate(storage.WebhookDto{Artifacts: []string{"com.acme:[core"}}); err == nil {
+		t.Error("Expected malformed pattern error")
+	}
+}
+
+func TestNextAttempt(t *testing.T) {
+	now := time.Now()
+	for attempts, expected := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 7: time.Hour, 20: time.Hour} {
+		if delay := NextAttempt(attempts, now).Sub(now); delay != expected {
+			t.Errorf("Attempt %d: expected %s, got %s", attempts, expected, delay)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HeaderSignature) != Sign("secret", received) || r.Header.Get(HeaderDelivery) != "1:hook" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Receiver is down for the first two attempts
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := &Sender{Client: server.Client(), Attempts: 3, Backoff: time.Millisecond}
+	if err := sender.Send(context.Background(), server.URL, "secret", "1:hook", body); err != nil {
+		t.Fatalf("Expected delivery after retries, got %s", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	sender.Attempts = 2
+	err := sender.Send(context.Background(), server.URL, "secret", "1:hook", body)
+	var deliveryErr *DeliveryError
+	if !errors.As(err, &deliveryErr) || deliveryErr.Attempts != 2 || deliveryErr.Permanent {
+		t.Errorf("Expected temporary failure after 2 attempts, got %v", err)
+	}
+
+	err = sender.Send(context.Background(), server.URL, "wrong", "1:hook", body)
+	if !errors.As(err, &deliveryErr) || deliveryErr.Attempts != 1 || !deliveryErr.Permanent {
+		t.Errorf("Expected permanent failure without retries, got %v", err)
+	}
+}
@@ -15,11 +15,84 @@ locals {
       authorizer_required = true
     },
 
+    "GET /dependency/{group}/{name}" = { # Will show all repositories with versions for specified group,name (listRepositoriesByDependency)
+      lambda              = module.lambda_repositories_list_by_dep.lambda_function_name
+      authorizer_required = true
+    },
+
     "GET /dependency/{group}/{name}/{version}" = { # Will show all repositories for specified group,name,version (listRepositoriesByGroupNameVersion)
       lambda              = module.lambda_repositories_list_by_dep.lambda_function_name
       authorizer_required = true
     },
 
+    "GET /impact/{group}/{name}/{version}" = { # Will show repositories affected by upgrade of group,name to version with major/minor/patch jump and intermediate dependency (graph.BlastRadius)
+      lambda              = module.lambda_impact.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /plugin" = { # Will show all plugins we have (listDependenciesByParent)
+      lambda              = module.lambda_plugins_list.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /plugin/{id}" = { # Will show all repositories with versions for specified plugin id (listRepositoriesByDependency)
+      lambda              = module.lambda_plugins_list.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /platform" = { # Will show all platforms/BOMs we have (listDependenciesByParent)
+      lambda              = module.lambda_platforms_list.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /platform/{group}/{name}" = { # Will show repositories with platform versions and overridden dependencies (listPlatformUsage)
+      lambda              = module.lambda_platforms_list.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /environment" = { # Will show gradle, java and kotlin versions grouped with repositories (listEnvironmentInventory)
+      lambda              = module.lambda_environment_inventory.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /vulnerability/{org}/{repo}/{ref+}" = { # Will show dependencies of org,repo,ref affected by imported OSV advisories (osv.Scan)
+      lambda              = module.lambda_vulnerabilities.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /advisory/{id}" = { # Will show repositories affected by advisory, id could be GHSA or CVE alias (osv.Scan)
+      lambda              = module.lambda_vulnerabilities.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /violation" = { # Will show policy violations of all repositories, ?rule= filters by rule (listViolations)
+      lambda              = module.lambda_violations.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /violation/{org}/{repo}/{ref+}" = { # Will show policy violations of org,repo,ref found on last upload (listViolations)
+      lambda              = module.lambda_violations.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /license" = { # Will show all licenses with category and usage counts (licenses.Inventory)
+      lambda              = module.lambda_licenses.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /license/{license}" = { # Will show repositories using license, id could be SPDX id, family (GPL, AGPL) or UNKNOWN (licenses.Inventory)
+      lambda              = module.lambda_licenses.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /license/{org}/{repo}/{ref+}" = { # Will show licenses of org,repo,ref dependencies (licenses.Inventory)
+      lambda              = module.lambda_licenses.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /freshness" = { # Will show libyears and share of current dependencies per org and repo/ref with trend, ?org= limits to single org (listFreshness)
+      lambda              = module.lambda_freshness.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /freshness/{org}/{repo}/{ref+}" = { # Will show daily freshness snapshots of org,repo,ref (listFreshness)
+      lambda              = module.lambda_freshness.lambda_function_name
+      authorizer_required = true
+    },
+
     "GET /repository" = { # Will show all repos we have (listRepositoriesByParent)
       lambda              = module.lambda_repository_list_by_parent.lambda_function_name
       authorizer_required = true
@@ -33,11 +106,74 @@ locals {
       authorizer_required = true
     },
 
+    "GET /repository-graph" = { # Will show repo->repo links through internal artifacts, producer is declared in upload or configured by group prefix (graph.RepoGraph)
+      lambda              = module.lambda_repository_graph.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /repository-graph/{org}/{repo}" = { # Will show repositories org,repo depends on (upstream) and repositories depending on it (downstream) (graph.RepoGraph)
+      lambda              = module.lambda_repository_graph.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /conflict" = { # Will show duplicate-class and split-artifact conflicts of all repositories, built-in rules plus policy ones (conflicts.Rules)
+      lambda              = module.lambda_conflicts.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /conflict/{org}/{repo}/{ref+}" = { # Will show conflict warnings of repo/ref
+      lambda              = module.lambda_conflicts.lambda_function_name
+      authorizer_required = true
+    },
+
     "PUT /api/v1/repository/{org}/{repo}/{ref+}" = {
       lambda              = module.lambda_repo_batch_insert_put.lambda_function_name
       authorizer_required = true
     },
 
+    "POST /api/v1/tokens" = { # Creates token for authenticated user, token itself is returned only once
+      lambda              = module.lambda_api_tokens.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /api/v1/tokens" = { # Lists tokens of authenticated user
+      lambda              = module.lambda_api_tokens.lambda_function_name
+      authorizer_required = true
+    },
+    "DELETE /api/v1/tokens/{id}" = { # Revokes token of authenticated user
+      lambda              = module.lambda_api_tokens.lambda_function_name
+      authorizer_required = true
+    },
+
+    "POST /api/v1/webhooks" = { # Admin only: subscribes url to dependency changes filtered by repos, refs and artifacts globs, secret is returned only once
+      lambda              = module.lambda_api_webhooks.lambda_function_name
+      authorizer_required = true
+    },
+    "GET /api/v1/webhooks" = { # Admin only: lists webhooks without secrets
+      lambda              = module.lambda_api_webhooks.lambda_function_name
+      authorizer_required = true
+    },
+    "DELETE /api/v1/webhooks/{id}" = { # Admin only: deletes webhook, its pending deliveries fail
+      lambda              = module.lambda_api_webhooks.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /api/v1/audit" = { # Admin only: audit entries filtered by ?principal=, ?repo=, ?from= and ?to= (listAudit)
+      lambda              = module.lambda_api_audit_list.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /api/v1/sarif/{org}/{repo}/{ref+}" = { # SARIF 2.1.0 log of vulnerable dependencies, policy violations and banned licenses, ?file= sets location of dependencies declared in unknown file
+      lambda              = module.lambda_api_sarif.lambda_function_name
+      authorizer_required = true
+    },
+
+    "GET /api/v1/policy" = { # Returns stored policy document (getPolicy)
+      lambda              = module.lambda_api_policy.lambda_function_name
+      authorizer_required = true
+    },
+    "PUT /api/v1/policy" = { # Admin only: replaces policy evaluated on upload, body is YAML or JSON document (putPolicy)
+      lambda              = module.lambda_api_policy.lambda_function_name
+      authorizer_required = true
+    },
+
     "$default" = {
       lambda = module.lambda_default.lambda_function_name
     },
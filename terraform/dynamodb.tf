@@ -98,3 +98,275 @@ resource "aws_dynamodb_table" "dependencies" {
     Name = "${var.name_prefix}-dependencies"
   }
 }
+
+resource "aws_dynamodb_table" "tokens" {
+  name         = "${var.name_prefix}-tokens"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "Id"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  attribute {
+    name = "Owner"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "Owner"
+    hash_key        = "Owner"
+    projection_type = "ALL"
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-tokens"
+  }
+}
+
+resource "aws_dynamodb_table" "audit" {
+  name         = "${var.name_prefix}-audit"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "Id"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  attribute {
+    name = "Principal"
+    type = "S"
+  }
+
+  attribute {
+    name = "Repo"
+    type = "S"
+  }
+
+  attribute {
+    name = "Time"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "Principal"
+    hash_key        = "Principal"
+    range_key       = "Time"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "Repo"
+    hash_key        = "Repo"
+    range_key       = "Time"
+    projection_type = "ALL"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-audit"
+  }
+}
+
+resource "aws_dynamodb_table" "advisories" {
+  name         = "${var.name_prefix}-advisories"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "Package"
+  range_key = "Id"
+
+  attribute {
+    name = "Package"
+    type = "S"
+  }
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-advisories"
+  }
+}
+
+resource "aws_dynamodb_table" "policies" {
+  name         = "${var.name_prefix}-policies"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "Id"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-policies"
+  }
+}
+
+resource "aws_dynamodb_table" "violations" {
+  name         = "${var.name_prefix}-violations"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "Id"
+  range_key = "Key"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  attribute {
+    name = "Key"
+    type = "S"
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-violations"
+  }
+}
+
+resource "aws_dynamodb_table" "licenses" {
+  name         = "${var.name_prefix}-licenses"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "Dependency"
+  range_key = "Version"
+
+  attribute {
+    name = "Dependency"
+    type = "S"
+  }
+
+  attribute {
+    name = "Version"
+    type = "S"
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-licenses"
+  }
+}
+
+resource "aws_dynamodb_table" "metadata" {
+  name         = "${var.name_prefix}-metadata"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "Dependency"
+  range_key = "Version"
+
+  attribute {
+    name = "Dependency"
+    type = "S"
+  }
+
+  attribute {
+    name = "Version"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "Expires"
+    enabled        = true
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-metadata"
+  }
+}
+
+resource "aws_dynamodb_table" "freshness" {
+  name         = "${var.name_prefix}-freshness"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "Id"
+  range_key = "Date"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  attribute {
+    name = "Date"
+    type = "S"
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-freshness"
+  }
+}
+
+resource "aws_dynamodb_table" "producers" {
+  name         = "${var.name_prefix}-producers"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "Artifact"
+
+  attribute {
+    name = "Artifact"
+    type = "S"
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-producers"
+  }
+}
+
+resource "aws_dynamodb_table" "webhooks" {
+  name         = "${var.name_prefix}-webhooks"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "Id"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-webhooks"
+  }
+}
+
+resource "aws_dynamodb_table" "deliveries" {
+  name         = "${var.name_prefix}-deliveries"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "Id"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  # New deliveries trigger webhook dispatcher
+  stream_enabled   = true
+  stream_view_type = "KEYS_ONLY"
+
+  ttl {
+    attribute_name = "Expires"
+    enabled        = true
+  }
+
+  tags = {
+    Name = "${var.name_prefix}-deliveries"
+  }
+}
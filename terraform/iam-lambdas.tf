@@ -25,6 +25,7 @@ data "aws_iam_policy_document" "lambdas_policy" {
       "dynamodb:GetItem",
       "dynamodb:BatchGetItem",
       "dynamodb:Query",
+      "dynamodb:Scan",
       "dynamodb:PutItem",
       "dynamodb:UpdateItem",
       "dynamodb:DeleteItem",
@@ -34,9 +35,44 @@ data "aws_iam_policy_document" "lambdas_policy" {
       aws_dynamodb_table.storage.arn,
       aws_dynamodb_table.repositories.arn,
       aws_dynamodb_table.dependencies.arn,
+      aws_dynamodb_table.tokens.arn,
+      aws_dynamodb_table.audit.arn,
+      aws_dynamodb_table.advisories.arn,
+      aws_dynamodb_table.policies.arn,
+      aws_dynamodb_table.violations.arn,
+      aws_dynamodb_table.licenses.arn,
+      aws_dynamodb_table.metadata.arn,
+      aws_dynamodb_table.freshness.arn,
+      aws_dynamodb_table.producers.arn,
+      aws_dynamodb_table.webhooks.arn,
+      aws_dynamodb_table.deliveries.arn,
       "${aws_dynamodb_table.storage.arn}/*",
       "${aws_dynamodb_table.repositories.arn}/*",
       "${aws_dynamodb_table.dependencies.arn}/*",
+      "${aws_dynamodb_table.tokens.arn}/*",
+      "${aws_dynamodb_table.audit.arn}/*",
+      "${aws_dynamodb_table.advisories.arn}/*",
+      "${aws_dynamodb_table.policies.arn}/*",
+      "${aws_dynamodb_table.violations.arn}/*",
+      "${aws_dynamodb_table.licenses.arn}/*",
+      "${aws_dynamodb_table.metadata.arn}/*",
+      "${aws_dynamodb_table.freshness.arn}/*",
+      "${aws_dynamodb_table.producers.arn}/*",
+      "${aws_dynamodb_table.webhooks.arn}/*",
+      "${aws_dynamodb_table.deliveries.arn}/*",
+    ]
+  }
+
+  statement {
+    sid = "AllowDynamoDBStreams"
+    actions = [
+      "dynamodb:DescribeStream",
+      "dynamodb:GetRecords",
+      "dynamodb:GetShardIterator",
+      "dynamodb:ListStreams",
+    ]
+    resources = [
+      aws_dynamodb_table.deliveries.stream_arn,
     ]
   }
 
@@ -0,0 +1,29 @@
+module "lambda_api_policy" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-api-policy"
+  description   = "Gradle Dependencies: /api/v1/policy"
+  handler       = "api-policy"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5
+
+  environment_variables = {
+    DYNAMODB_TABLE_POLICIES = aws_dynamodb_table.policies.id
+    DYNAMODB_TABLE_AUDIT    = aws_dynamodb_table.audit.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/api-policy"
+
+  tags = merge({
+    Name = "${var.name_prefix}-api-policy"
+  }, var.tags)
+}
@@ -14,6 +14,13 @@ module "lambda_repo_batch_insert_put" {
     DYNAMODB_TABLE_STORAGE      = aws_dynamodb_table.storage.id
     DYNAMODB_TABLE_REPOSITORIES = aws_dynamodb_table.repositories.id
     DYNAMODB_TABLE_DEPENDENCIES = aws_dynamodb_table.dependencies.id
+    DYNAMODB_TABLE_AUDIT        = aws_dynamodb_table.audit.id
+    DYNAMODB_TABLE_POLICIES     = aws_dynamodb_table.policies.id
+    DYNAMODB_TABLE_VIOLATIONS   = aws_dynamodb_table.violations.id
+    DYNAMODB_TABLE_LICENSES     = aws_dynamodb_table.licenses.id
+    DYNAMODB_TABLE_PRODUCERS    = aws_dynamodb_table.producers.id
+    DYNAMODB_TABLE_WEBHOOKS     = aws_dynamodb_table.webhooks.id
+    DYNAMODB_TABLE_DELIVERIES   = aws_dynamodb_table.deliveries.id
   }
 
   create_role = false
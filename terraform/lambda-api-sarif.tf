@@ -0,0 +1,30 @@
+module "lambda_api_sarif" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-api-sarif"
+  description   = "Gradle Dependencies: /api/v1/sarif/{org}/{repo}/{ref+}"
+  handler       = "api-sarif"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE    = aws_dynamodb_table.storage.id
+    DYNAMODB_TABLE_ADVISORIES = aws_dynamodb_table.advisories.id
+    DYNAMODB_TABLE_VIOLATIONS = aws_dynamodb_table.violations.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/api-sarif"
+
+  tags = merge({
+    Name = "${var.name_prefix}-api-sarif"
+  }, var.tags)
+}
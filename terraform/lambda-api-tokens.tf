@@ -0,0 +1,29 @@
+module "lambda_api_tokens" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-api-tokens"
+  description   = "Gradle Dependencies: /api/v1/tokens"
+  handler       = "api-tokens"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5
+
+  environment_variables = {
+    DYNAMODB_TABLE_TOKENS = aws_dynamodb_table.tokens.id
+    DYNAMODB_TABLE_AUDIT  = aws_dynamodb_table.audit.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/api-tokens"
+
+  tags = merge({
+    Name = "${var.name_prefix}-api-tokens"
+  }, var.tags)
+}
@@ -10,7 +10,20 @@ module "lambda_authorizer" {
   memory_size = 256
   timeout     = 5
 
-  environment_variables = { for user_name, user_obj in var.users : "USER_${upper(md5(user_name))}" => md5(user_obj.password) }
+  environment_variables = merge(
+    { for user_name, user_obj in var.users : "USER_${upper(md5(user_name))}" => md5(user_obj.password) },
+    { for user_name, user_obj in var.hashed_users : "USER_${upper(sha256(user_name))}" => user_obj.password_hash },
+    { for user_name, user_obj in var.hashed_users : "ROLE_${upper(sha256(user_name))}" => user_obj.role },
+    { for user_name, user_obj in var.users : "ROLE_${upper(sha256(user_name))}" => "uploader" },
+    { for user_name, scopes in var.user_scopes : "SCOPES_${upper(sha256(user_name))}" => join(",", scopes) },
+    { DYNAMODB_TABLE_TOKENS = aws_dynamodb_table.tokens.id },
+    try({
+      OIDC_ISSUER               = var.oidc.issuer
+      OIDC_AUDIENCE             = var.oidc.audience
+      OIDC_JWKS_URL             = var.oidc.jwks_url
+      OIDC_ALLOWED_REPOSITORIES = join(",", var.oidc.allowed_repositories)
+    }, {}),
+  )
 
   create_role = false
   lambda_role = module.lambdas_role.iam_role_arn
@@ -0,0 +1,57 @@
+module "lambda_cron_freshness" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-cron-freshness"
+  description   = "Gradle: daily dependency freshness snapshot"
+  handler       = "cron-freshness"
+  runtime       = "go1.x"
+
+  memory_size = 512
+  timeout     = 900
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE   = aws_dynamodb_table.storage.id
+    DYNAMODB_TABLE_METADATA  = aws_dynamodb_table.metadata.id
+    DYNAMODB_TABLE_FRESHNESS = aws_dynamodb_table.freshness.id
+    MAVEN_MIRROR_URL         = var.maven_mirror_url
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/cron-freshness"
+
+  tags = merge({
+    Name = "${var.name_prefix}-cron-freshness"
+  }, var.tags)
+}
+
+resource "aws_cloudwatch_event_rule" "freshness" {
+  name                = "${var.name_prefix}-freshness"
+  description         = "Daily dependency freshness snapshot"
+  schedule_expression = var.freshness_schedule
+  is_enabled          = var.maven_mirror_url != ""
+
+  tags = merge({
+    Name = "${var.name_prefix}-freshness"
+  }, var.tags)
+}
+
+resource "aws_cloudwatch_event_target" "freshness" {
+  rule = aws_cloudwatch_event_rule.freshness.name
+  arn  = module.lambda_cron_freshness.lambda_function_arn
+}
+
+resource "aws_lambda_permission" "freshness" {
+  function_name = module.lambda_cron_freshness.lambda_function_name
+
+  statement_id = "AllowInvokeFromEventBridge"
+  action       = "lambda:InvokeFunction"
+  principal    = "events.amazonaws.com"
+
+  source_arn = aws_cloudwatch_event_rule.freshness.arn
+}
@@ -0,0 +1,29 @@
+module "lambda_conflicts" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-conflicts"
+  description   = "Gradle: GET /conflict, GET /conflict/{org}/{repo}/{ref+}"
+  handler       = "web-conflicts"
+  runtime       = "go1.x"
+
+  memory_size = 512
+  timeout     = 30 # Storage is scanned for report of all repositories
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE  = aws_dynamodb_table.storage.id
+    DYNAMODB_TABLE_POLICIES = aws_dynamodb_table.policies.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-conflicts"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-conflicts"
+  }, var.tags)
+}
@@ -8,12 +8,14 @@ module "lambda_dependencies_list_by_repo" {
   runtime       = "go1.x"
 
   memory_size = 256
-  timeout     = 5
+  timeout     = 10 # Maven mirror lookups are cut after 3 seconds
 
   environment_variables = {
     DYNAMODB_TABLE_STORAGE      = aws_dynamodb_table.storage.id
     DYNAMODB_TABLE_REPOSITORIES = aws_dynamodb_table.repositories.id
     DYNAMODB_TABLE_DEPENDENCIES = aws_dynamodb_table.dependencies.id
+    DYNAMODB_TABLE_METADATA     = aws_dynamodb_table.metadata.id
+    MAVEN_MIRROR_URL            = var.maven_mirror_url
   }
 
   create_role = false
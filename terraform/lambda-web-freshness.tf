@@ -0,0 +1,28 @@
+module "lambda_freshness" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-freshness"
+  description   = "Gradle: GET /freshness, GET /freshness/{org}/{repo}/{ref+}"
+  handler       = "web-freshness"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 10 # Dashboard scans all snapshots
+
+  environment_variables = {
+    DYNAMODB_TABLE_FRESHNESS = aws_dynamodb_table.freshness.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-freshness"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-freshness"
+  }, var.tags)
+}
@@ -0,0 +1,28 @@
+module "lambda_impact" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-impact"
+  description   = "Gradle: GET /impact/{group}/{name}/{version}"
+  handler       = "web-impact"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 15 # Dependencies of every affected repo/ref are queried
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE = aws_dynamodb_table.storage.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-impact"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-impact"
+  }, var.tags)
+}
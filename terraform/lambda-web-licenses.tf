@@ -0,0 +1,28 @@
+module "lambda_licenses" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-licenses"
+  description   = "Gradle: GET /license, GET /license/{license}, GET /license/{org}/{repo}/{ref+}"
+  handler       = "web-licenses"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5 # TODO scan of storage table for /license and /license/{license} grows with data
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE = aws_dynamodb_table.storage.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-licenses"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-licenses"
+  }, var.tags)
+}
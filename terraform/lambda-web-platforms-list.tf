@@ -0,0 +1,30 @@
+module "lambda_platforms_list" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-platforms-list"
+  description   = "Gradle: GET /platform/{group}/{name}"
+  handler       = "web-platforms-list"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE      = aws_dynamodb_table.storage.id
+    DYNAMODB_TABLE_REPOSITORIES = aws_dynamodb_table.repositories.id
+    DYNAMODB_TABLE_DEPENDENCIES = aws_dynamodb_table.dependencies.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-platforms-list"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-platforms-list"
+  }, var.tags)
+}
@@ -0,0 +1,30 @@
+module "lambda_repository_graph" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-repository-graph"
+  description   = "Gradle: GET /repository-graph, GET /repository-graph/{org}/{repo}"
+  handler       = "web-repository-graph"
+  runtime       = "go1.x"
+
+  memory_size = 512
+  timeout     = 30 # Storage is scanned, there is no index of internal artifacts usages
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE   = aws_dynamodb_table.storage.id
+    DYNAMODB_TABLE_PRODUCERS = aws_dynamodb_table.producers.id
+    PRODUCER_PREFIXES        = join(",", [for prefix, repo in var.producer_prefixes : "${prefix}=${repo}"])
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-repository-graph"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-repository-graph"
+  }, var.tags)
+}
@@ -0,0 +1,28 @@
+module "lambda_violations" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-violations"
+  description   = "Gradle: GET /violation, GET /violation/{org}/{repo}/{ref+}"
+  handler       = "web-violations"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5
+
+  environment_variables = {
+    DYNAMODB_TABLE_VIOLATIONS = aws_dynamodb_table.violations.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-violations"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-violations"
+  }, var.tags)
+}
@@ -0,0 +1,29 @@
+module "lambda_vulnerabilities" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-web-vulnerabilities"
+  description   = "Gradle: GET /vulnerability/{org}/{repo}/{ref+}, GET /advisory/{id}"
+  handler       = "web-vulnerabilities"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 5
+
+  environment_variables = {
+    DYNAMODB_TABLE_STORAGE    = aws_dynamodb_table.storage.id
+    DYNAMODB_TABLE_ADVISORIES = aws_dynamodb_table.advisories.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/web-vulnerabilities"
+
+  tags = merge({
+    Name = "${var.name_prefix}-web-vulnerabilities"
+  }, var.tags)
+}
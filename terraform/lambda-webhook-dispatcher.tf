@@ -0,0 +1,63 @@
+module "lambda_webhook_dispatcher" {
+  source  = "terraform-aws-modules/lambda/aws"
+  version = "v2.0.0"
+
+  function_name = "${var.name_prefix}-webhook-dispatcher"
+  description   = "Gradle: sends webhook deliveries, new ones from table stream and retries by schedule"
+  handler       = "webhook-dispatcher"
+  runtime       = "go1.x"
+
+  memory_size = 256
+  timeout     = 300 # Every delivery is attempted up to 3 times with backoff before it is rescheduled
+
+  environment_variables = {
+    DYNAMODB_TABLE_WEBHOOKS   = aws_dynamodb_table.webhooks.id
+    DYNAMODB_TABLE_DELIVERIES = aws_dynamodb_table.deliveries.id
+  }
+
+  create_role = false
+  lambda_role = module.lambdas_role.iam_role_arn
+
+  attach_cloudwatch_logs_policy     = true
+  cloudwatch_logs_retention_in_days = 7
+
+  source_path = "${var.distrib_path}/webhook-dispatcher"
+
+  tags = merge({
+    Name = "${var.name_prefix}-webhook-dispatcher"
+  }, var.tags)
+}
+
+resource "aws_lambda_event_source_mapping" "deliveries" {
+  event_source_arn  = aws_dynamodb_table.deliveries.stream_arn
+  function_name     = module.lambda_webhook_dispatcher.lambda_function_arn
+  starting_position = "LATEST"
+  batch_size        = 5
+
+  maximum_retry_attempts = 3
+}
+
+resource "aws_cloudwatch_event_rule" "webhook_retries" {
+  name                = "${var.name_prefix}-webhook-retries"
+  description         = "Retries of failed webhook deliveries"
+  schedule_expression = var.webhook_retry_schedule
+
+  tags = merge({
+    Name = "${var.name_prefix}-webhook-retries"
+  }, var.tags)
+}
+
+resource "aws_cloudwatch_event_target" "webhook_retries" {
+  rule = aws_cloudwatch_event_rule.webhook_retries.name
+  arn  = module.lambda_webhook_dispatcher.lambda_function_arn
+}
+
+resource "aws_lambda_permission" "webhook_retries" {
+  function_name = module.lambda_webhook_dispatcher.lambda_function_name
+
+  statement_id = "AllowInvokeFromEventBridge"
+  action       = "lambda:InvokeFunction"
+  principal    = "events.amazonaws.com"
+
+  source_arn = aws_cloudwatch_event_rule.webhook_retries.arn
+}
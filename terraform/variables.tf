@@ -53,6 +53,63 @@ variable "users" {
   type = map(object({
     password = string
   }))
+  description = "Deprecated: users stored as unsalted md5 with uploader role, move them to `hashed_users`"
+  default     = {}
+}
+
+variable "hashed_users" {
+  type = map(object({
+    password_hash = string
+    role          = string
+  }))
+  description = "Users with bcrypt password hashes (generate with `gdg hash-password`) and role: reader, uploader or admin"
+  default     = {}
+
+  validation {
+    condition     = alltrue([for user_obj in values(var.hashed_users) : contains(["reader", "uploader", "admin"], user_obj.role)])
+    error_message = "User role must be one of reader, uploader or admin."
+  }
+}
+
+variable "user_scopes" {
+  type        = map(list(string))
+  description = "Upload scopes per user as org/repo[:ref] globs, e.g. { ci-acme = [\"acme/*\"] }, users without scopes may upload to any repository"
+  default     = {}
+}
+
+variable "oidc" {
+  type = object({
+    issuer               = string
+    audience             = string
+    jwks_url             = string
+    allowed_repositories = list(string)
+  })
+  description = "OIDC provider of CI jobs, e.g. GitHub Actions: issuer https://token.actions.githubusercontent.com, jwks_url https://token.actions.githubusercontent.com/.well-known/jwks. allowed_repositories are org/repo globs or orgs (GitLab groups) whose jobs may upload, required because the issuer is shared by all repositories"
+  default     = null
+}
+
+variable "maven_mirror_url" {
+  type        = string
+  description = "Maven repository with maven-metadata.xml used to show outdated dependencies, e.g. https://repo1.maven.org/maven2, empty disables"
+  default     = ""
+}
+
+variable "freshness_schedule" {
+  type        = string
+  description = "Schedule of dependency freshness snapshots, runs only when maven_mirror_url is set"
+  default     = "cron(0 3 * * ? *)"
+}
+
+variable "webhook_retry_schedule" {
+  type        = string
+  description = "Schedule of retries of failed webhook deliveries, the first attempt is made right after upload"
+  default     = "rate(5 minutes)"
+}
+
+variable "producer_prefixes" {
+  type        = map(string)
+  description = "Repositories publishing internal artifacts by group prefix, e.g. { \"com.acme.payments\" = \"acme/payments\" }, artifacts declared as produces in uploads take precedence"
+  default     = {}
 }
 
 variable "tags" {